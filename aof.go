@@ -0,0 +1,801 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+const (
+	aofMagic      = "GOCACHE-AOF"
+	aofVersion    = 1
+	aofSyncPeriod = time.Second
+)
+
+const (
+	aofSetMarker byte = iota + 1
+	aofDeleteMarker
+	aofClearMarker
+)
+
+// it's combined with a marker of a record of a namespace, which is followed
+// by the path of the namespace
+const aofNamespaceFlag byte = 0x80
+
+// ...
+var (
+	ErrAOFNotSet             = errors.New("AOF not set")
+	ErrAOFClosed             = errors.New("AOF closed")
+	ErrAOFRewriteInProgress  = errors.New("AOF rewrite in progress")
+	ErrInvalidAOF            = errors.New("invalid AOF")
+	ErrUnsupportedAOFVersion = errors.New("unsupported AOF version")
+	ErrCorruptedAOFTail      = errors.New("corrupted AOF tail")
+)
+
+var (
+	aofHeader = binary.AppendUvarint([]byte(aofMagic), aofVersion)
+
+	errCorruptedAOFRecord = errors.New("corrupted AOF record")
+)
+
+// AOF ...
+//
+// It's an append-only file, to which changes of values of a cache are logged
+// (see the WithAOF() option), so that the latter can be restored after
+// a restart, including changes made since the last snapshot.
+//
+// The file starts with a header that contains its format version. Each record
+// has its own checksum, so a truncated or corrupted tail of the file
+// (e.g., after a crash during writing) is detected.
+//
+type AOF struct {
+	path  string
+	codec SnapshotCodec
+
+	fsyncPolicy    FsyncPolicy
+	isStrictReplay bool
+
+	lock sync.Mutex
+	file *os.File
+	// it's set while the rewrite is running and receives records appended
+	// meanwhile, so that they are moved to the rewritten file
+	rewriteBuffer *bytes.Buffer
+	isDirty       bool
+	isClosed      bool
+	err           error
+
+	stopSyncing chan struct{}
+	syncingDone chan struct{}
+}
+
+// OpenAOF ...
+//
+// It opens the file at the path or creates it. The codec is used for keys
+// and data of records, the same way as for snapshots.
+//
+// For the FsyncPolicyEverySecond policy, it starts flushing in background,
+// which is stopped by the Close() method.
+//
+// The error can be ErrInvalidAOF (including for a truncated header),
+// ErrUnsupportedAOFVersion or an error of the file.
+//
+func OpenAOF(
+	path string,
+	codec SnapshotCodec,
+	options ...AOFOption,
+) (*AOF, error) {
+	// default config
+	config := aofConfig{fsyncPolicy: FsyncPolicyEverySecond}
+	for _, option := range options {
+		option(&config)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := initAOFFile(file); err != nil {
+		file.Close() // nolint: errcheck
+		return nil, err
+	}
+
+	aof := &AOF{
+		path:  path,
+		codec: codec,
+
+		fsyncPolicy:    config.fsyncPolicy,
+		isStrictReplay: config.isStrictReplay,
+
+		file: file,
+	}
+	if aof.fsyncPolicy == FsyncPolicyEverySecond {
+		aof.stopSyncing, aof.syncingDone = make(chan struct{}), make(chan struct{})
+		go aof.runSyncing()
+	}
+
+	return aof, nil
+}
+
+// Sync ...
+//
+// It flushes the file to a disk regardless of the fsync policy.
+//
+// The error can be ErrAOFClosed, an error of the flushing or a previous
+// error of the logging (see the WithAOF() option).
+//
+func (aof *AOF) Sync() error {
+	aof.lock.Lock()
+	defer aof.lock.Unlock()
+
+	if aof.isClosed {
+		return ErrAOFClosed
+	}
+
+	aof.sync()
+	return aof.err
+}
+
+// Close ...
+//
+// It stops flushing in background, flushes the file to a disk and closes it.
+// Changes made after closing aren't logged.
+//
+// The error can be ErrAOFClosed, an error of the flushing or the closing
+// or a previous error of the logging (see the WithAOF() option).
+//
+func (aof *AOF) Close() error {
+	aof.lock.Lock()
+	if aof.isClosed {
+		aof.lock.Unlock()
+		return ErrAOFClosed
+	}
+
+	aof.isClosed = true
+	aof.lock.Unlock()
+
+	// the flushing in background locks the AOF, so it should be stopped
+	// without the lock
+	if aof.stopSyncing != nil {
+		close(aof.stopSyncing)
+		<-aof.syncingDone
+	}
+
+	aof.lock.Lock()
+	defer aof.lock.Unlock()
+
+	aof.sync()
+	err := aof.err
+	if closingErr := aof.file.Close(); err == nil {
+		err = closingErr
+	}
+
+	return err
+}
+
+// ReplayAOF ...
+//
+// It applies the changes logged to the AOF (see the WithAOF() option)
+// to the cache and its namespaces, skipping values that have already expired.
+// The replayed changes aren't logged again. It should be called on startup
+// before other use of the cache.
+//
+// Namespaces (see the Namespace() method) missing at the moment are created
+// by the replay without options, so ones with options should be created
+// before it. The replay is available for the cache only, not for views
+// of its namespaces.
+//
+// Accesses of values, including the Touch() method, aren't logged,
+// so the eviction policy can't reproduce its choice of victims. Therefore,
+// the maximal size and cost are applied only after the replay. For the same
+// reason, expiration on idleness is restarted: access times of replayed
+// values are reset to the time of the replay, so a value that was still
+// being accessed isn't dropped.
+//
+// A truncated or corrupted tail of the AOF (i.e., everything since the first
+// such record) is truncated, unless the AOFWithStrictReplay() option is set.
+//
+// The error can be ErrAOFNotSet (including for a view of a namespace),
+// ErrAOFClosed, ErrCorruptedAOFTail, ErrInvalidAOF or an error of the file
+// or the codec.
+//
+func (cache Cache) ReplayAOF() error {
+	if cache.aof == nil || cache.namespacePath != nil {
+		return ErrAOFNotSet
+	}
+
+	currentTime := cache.clock()
+	err := cache.aof.replay(func(record aofRecord) {
+		replayingCache := cache.replayingView(record.namespacePath, currentTime)
+		switch record.marker {
+		case aofSetMarker:
+			if record.entry.value.IdleTimeout != 0 {
+				record.entry.value.AccessTime =
+					models.NewAccessTime(replayingCache.clock())
+			}
+			if record.entry.value.IsExpired(replayingCache.clock) {
+				// the expired value could replace a live one
+				replayingCache.deleteIf(
+					record.entry.key,
+					RemovalReasonReplaced,
+					func(value models.Value) bool { return true },
+				)
+
+				return
+			}
+
+			replayingCache.setValue(record.entry.key, record.entry.value)
+		case aofDeleteMarker:
+			replayingCache.Delete(record.entry.key)
+		case aofClearMarker:
+			replayingCache.Clear()
+		}
+	})
+	cache.evictReplayed()
+
+	return err
+}
+
+// RewriteAOF ...
+//
+// It rewrites the AOF (see the WithAOF() option) with the current live values
+// of the cache and its namespaces only, so that its size doesn't grow
+// infinitely. The new file is written next to the current one in the calling
+// goroutine and replaces the latter at the end. The rewrite is available
+// for the cache only, not for views of its namespaces.
+//
+// The rewrite doesn't block writers: changes made meanwhile are logged
+// both to the current file and to a memory buffer, and the latter is moved
+// to the new file before the replacing. Writers are blocked only
+// for the moving.
+//
+// The error can be ErrAOFNotSet (including for a view of a namespace),
+// ErrAOFClosed, ErrAOFRewriteInProgress, an error of the file or the codec
+// or a previous error of the logging.
+//
+func (cache Cache) RewriteAOF() error {
+	if cache.aof == nil || cache.namespacePath != nil {
+		return ErrAOFNotSet
+	}
+
+	return cache.aof.rewrite(func(writer io.Writer) error {
+		cache = cache.withCurrentTime(cache.clock())
+
+		var err error
+		cache.storage.Iterate(func(key hashmap.Key, data interface{}) bool {
+			value := data.(models.Value)
+			view, namespacePath, key, ok := cache.resolveNamespacedKey(key)
+			if !ok ||
+				view.isCleared(value) ||
+				value.IsExpired(cache.clock) ||
+				value.Err != nil {
+				return true
+			}
+
+			var record []byte
+			record, err = appendAOFRecord(
+				nil,
+				aofSetMarker,
+				namespacePath,
+				key,
+				value,
+				cache.aof.codec,
+			)
+			if err != nil {
+				return false
+			}
+
+			_, err = writer.Write(record)
+			return err == nil
+		})
+
+		return err
+	})
+}
+
+// it's called after writing to the storage under the key locks, so records
+// of a key are appended in order of its changes, and the rewrite can't miss
+// a change that it doesn't see in the storage
+func (cache Cache) logChanges(transactions []keyTransaction) {
+	if cache.aof == nil {
+		return
+	}
+
+	var records []byte
+	for _, transaction := range transactions {
+		if !transaction.isChanged {
+			continue
+		}
+
+		var err error
+		switch {
+		case transaction.isPresent &&
+			transaction.data.(models.Value).Err == nil:
+			records, err = appendAOFRecord(
+				records,
+				aofSetMarker,
+				cache.namespacePath,
+				transaction.key,
+				transaction.data.(models.Value),
+				cache.aof.codec,
+			)
+		// negative values aren't logged, but they replace previous ones
+		case transaction.isPresent || !transaction.isDeletionUnlogged:
+			records, err = appendAOFRecord(
+				records,
+				aofDeleteMarker,
+				cache.namespacePath,
+				transaction.key,
+				models.Value{},
+				cache.aof.codec,
+			)
+		}
+		if err != nil {
+			cache.aof.fail(err)
+			return
+		}
+	}
+
+	if len(records) != 0 {
+		cache.aof.append(records)
+	}
+}
+
+// it should be called under all the key locks
+func (cache Cache) logClearing() {
+	if cache.aof == nil {
+		return
+	}
+
+	// a record of the clearing doesn't use the codec, so it can't fail
+	record, _ := appendAOFRecord(
+		nil,
+		aofClearMarker,
+		cache.namespacePath,
+		nil,
+		models.Value{},
+		cache.aof.codec,
+	)
+	cache.aof.append(record)
+}
+
+// it returns the view of the namespace at the path with the frozen clock,
+// without logging and without limits; the namespaces are got from the cache
+// itself, so the clock of created ones isn't frozen
+func (cache Cache) replayingView(
+	namespacePath []string,
+	currentTime time.Time,
+) Cache {
+	view := cache.namespaceView(namespacePath).withCurrentTime(currentTime)
+	view.aof = nil
+	view.maxSize, view.maxCost = 0, 0
+
+	return view
+}
+
+// the limits aren't applied during the replay, so they are applied after it
+// to the cache and to all its namespaces, including nested ones
+func (cache Cache) evictReplayed() {
+	if cache.isBounded() {
+		cache.evict()
+	}
+
+	for _, namespace := range cache.namespaces.all() {
+		namespace.evictReplayed()
+	}
+}
+
+type aofRecord struct {
+	marker byte
+	// it's empty for a record of the cache itself
+	namespacePath []string
+	// it contains the key only for the deletion
+	entry snapshotEntry
+}
+
+// it appends the record to the buffer; the record contains a length
+// of its payload, the payload and a CRC-32 checksum of both
+func appendAOFRecord(
+	buffer []byte,
+	marker byte,
+	namespacePath []string,
+	key hashmap.Key,
+	value models.Value,
+	codec SnapshotCodec,
+) ([]byte, error) {
+	var payload bytes.Buffer
+	writer := newSnapshotWriter(&payload)
+	if len(namespacePath) == 0 {
+		writer.write([]byte{marker})
+	} else {
+		writer.write([]byte{marker | aofNamespaceFlag})
+		writer.writeNamespacePath(namespacePath)
+	}
+
+	var err error
+	switch marker {
+	case aofSetMarker:
+		err = writer.writeEntry(key, value, codec)
+	case aofDeleteMarker:
+		err = writer.writeKey(key, codec)
+	}
+	if err == nil {
+		err = writer.flush()
+	}
+	if err != nil {
+		return buffer, err
+	}
+
+	recordStart := len(buffer)
+	buffer = binary.AppendUvarint(buffer, uint64(payload.Len()))
+	buffer = append(buffer, payload.Bytes()...)
+
+	checksum := crc32.Checksum(buffer[recordStart:], snapshotChecksumTable)
+	return binary.BigEndian.AppendUint32(buffer, checksum), nil
+}
+
+// it returns the payload of the next record and a length of the whole record;
+// errCorruptedAOFRecord is returned for a truncated or corrupted record
+func readAOFRecord(reader *bufio.Reader, remainingLength int64) (
+	payload []byte,
+	recordLength int64,
+	err error,
+) {
+	prefix, err := reader.Peek(binary.MaxVarintLen64)
+	if err != nil && err != io.EOF {
+		return nil, 0, err
+	}
+
+	payloadLength, payloadLengthSize := binary.Uvarint(prefix)
+	if payloadLengthSize <= 0 || payloadLength > uint64(remainingLength) {
+		return nil, 0, errCorruptedAOFRecord
+	}
+
+	recordLength =
+		int64(payloadLengthSize) + int64(payloadLength) + crc32.Size
+	if recordLength > remainingLength {
+		return nil, 0, errCorruptedAOFRecord
+	}
+
+	// the length is limited by the file size, so the allocation is safe
+	record := make([]byte, recordLength)
+	if _, err := io.ReadFull(reader, record); err != nil {
+		return nil, 0, err
+	}
+
+	checksumStart := recordLength - crc32.Size
+	checksum := crc32.Checksum(record[:checksumStart], snapshotChecksumTable)
+	if binary.BigEndian.Uint32(record[checksumStart:]) != checksum {
+		return nil, 0, errCorruptedAOFRecord
+	}
+
+	return record[payloadLengthSize:checksumStart], recordLength, nil
+}
+
+// the payload is already verified by the checksum, so its errors
+// aren't considered a corrupted tail
+func decodeAOFRecord(payload []byte, codec SnapshotCodec) (aofRecord, error) {
+	reader := newSnapshotReader(bytes.NewReader(payload))
+	record := aofRecord{marker: reader.readMarker()}
+	if record.marker&aofNamespaceFlag != 0 {
+		record.marker &^= aofNamespaceFlag
+		record.namespacePath = reader.readNamespacePath()
+	}
+
+	if reader.err != nil {
+		return aofRecord{}, fmt.Errorf("%w: %w", ErrInvalidAOF, reader.err)
+	}
+
+	var err error
+	switch record.marker {
+	case aofSetMarker:
+		record.entry, err = reader.readEntry(codec)
+	case aofDeleteMarker:
+		record.entry.key, err = reader.readKey(codec)
+	case aofClearMarker:
+	default:
+		return aofRecord{}, fmt.Errorf(
+			"%w: unknown marker %d",
+			ErrInvalidAOF,
+			record.marker,
+		)
+	}
+	if errors.Is(err, ErrInvalidSnapshot) {
+		return aofRecord{}, fmt.Errorf("%w: %w", ErrInvalidAOF, err)
+	}
+	if err != nil {
+		return aofRecord{}, err
+	}
+
+	return record, nil
+}
+
+// it writes the header to an empty file and checks the header
+// of a non-empty one
+func initAOFFile(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() == 0 {
+		if _, err := file.Write(aofHeader); err != nil {
+			return err
+		}
+
+		return file.Sync()
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, 0, info.Size()))
+	magic := make([]byte, len(aofMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return wrapAOFHeaderReadingError(err)
+	}
+	if string(magic) != aofMagic {
+		return ErrInvalidAOF
+	}
+
+	version, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return wrapAOFHeaderReadingError(err)
+	}
+	if version != aofVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedAOFVersion, version)
+	}
+
+	return nil
+}
+
+// an unexpected end of the reader means a truncated header
+func wrapAOFHeaderReadingError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %w", ErrInvalidAOF, io.ErrUnexpectedEOF)
+	}
+
+	return err
+}
+
+func (aof *AOF) runSyncing() {
+	defer close(aof.syncingDone)
+
+	ticker := time.NewTicker(aofSyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			aof.lock.Lock()
+			if aof.isDirty {
+				aof.sync()
+			}
+			aof.lock.Unlock()
+		case <-aof.stopSyncing:
+			return
+		}
+	}
+}
+
+// it should be called under the lock; its error is kept
+func (aof *AOF) sync() {
+	if aof.err != nil {
+		return
+	}
+
+	aof.isDirty = false
+	aof.err = aof.file.Sync()
+}
+
+func (aof *AOF) append(records []byte) {
+	aof.lock.Lock()
+	defer aof.lock.Unlock()
+
+	if aof.isClosed || aof.err != nil {
+		return
+	}
+
+	if _, aof.err = aof.file.Write(records); aof.err != nil {
+		return
+	}
+	if aof.rewriteBuffer != nil {
+		aof.rewriteBuffer.Write(records) // nolint: errcheck
+	}
+
+	switch aof.fsyncPolicy {
+	case FsyncPolicyAlways:
+		aof.sync()
+	case FsyncPolicyEverySecond:
+		aof.isDirty = true
+	}
+}
+
+// it keeps the first error only
+func (aof *AOF) fail(err error) {
+	aof.lock.Lock()
+	defer aof.lock.Unlock()
+
+	if aof.err == nil {
+		aof.err = err
+	}
+}
+
+// it reads records up to the size of the file at the start, so changes
+// logged meanwhile aren't replayed
+func (aof *AOF) replay(handler func(record aofRecord)) error {
+	aof.lock.Lock()
+	if aof.isClosed {
+		aof.lock.Unlock()
+		return ErrAOFClosed
+	}
+
+	file := aof.file
+	info, err := file.Stat()
+	aof.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	offset := int64(len(aofHeader))
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, info.Size()-offset))
+	for offset < info.Size() {
+		payload, recordLength, err := readAOFRecord(reader, info.Size()-offset)
+		if err == errCorruptedAOFRecord {
+			return aof.truncateTail(offset)
+		}
+		if err != nil {
+			return err
+		}
+
+		record, err := decodeAOFRecord(payload, aof.codec)
+		if err != nil {
+			return err
+		}
+
+		handler(record)
+		offset += recordLength
+	}
+
+	return nil
+}
+
+func (aof *AOF) truncateTail(offset int64) error {
+	if aof.isStrictReplay {
+		return fmt.Errorf("%w: at offset %d", ErrCorruptedAOFTail, offset)
+	}
+
+	aof.lock.Lock()
+	defer aof.lock.Unlock()
+
+	if aof.isClosed {
+		return ErrAOFClosed
+	}
+
+	if err := aof.file.Truncate(offset); err != nil {
+		return err
+	}
+	// a file replaced by the rewrite isn't opened in the appending mode
+	if _, err := aof.file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	return aof.file.Sync()
+}
+
+func (aof *AOF) rewrite(writeState func(writer io.Writer) error) error {
+	aof.lock.Lock()
+	if err := aof.checkRewriting(); err != nil {
+		aof.lock.Unlock()
+		return err
+	}
+
+	aof.rewriteBuffer = new(bytes.Buffer)
+	aof.lock.Unlock()
+
+	file, err := writeRewrittenAOF(aof.path, writeState)
+
+	aof.lock.Lock()
+	defer aof.lock.Unlock()
+	defer func() { aof.rewriteBuffer = nil }()
+
+	if err != nil {
+		return err
+	}
+	if err := aof.replaceFile(file); err != nil {
+		file.Close()           // nolint: errcheck
+		os.Remove(file.Name()) // nolint: errcheck
+
+		return err
+	}
+
+	return syncDirectory(aof.path)
+}
+
+// it should be called under the lock
+func (aof *AOF) checkRewriting() error {
+	switch {
+	case aof.isClosed:
+		return ErrAOFClosed
+	case aof.err != nil:
+		return aof.err
+	case aof.rewriteBuffer != nil:
+		return ErrAOFRewriteInProgress
+	default:
+		return nil
+	}
+}
+
+// it should be called under the lock
+func (aof *AOF) replaceFile(file *os.File) error {
+	// the memory buffer is incomplete on an error of the logging
+	if aof.isClosed {
+		return ErrAOFClosed
+	}
+	if aof.err != nil {
+		return aof.err
+	}
+
+	if _, err := file.Write(aof.rewriteBuffer.Bytes()); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), aof.path); err != nil {
+		return err
+	}
+
+	previousFile := aof.file
+	aof.file, aof.isDirty = file, false
+	previousFile.Close() // nolint: errcheck
+
+	return nil
+}
+
+// it writes the new file next to the current one, so that the former
+// can replace the latter atomically
+func writeRewrittenAOF(
+	path string,
+	writeState func(writer io.Writer) error,
+) (*os.File, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".rewrite-*")
+	if err != nil {
+		return nil, err
+	}
+
+	writer := bufio.NewWriter(file)
+	_, err = writer.Write(aofHeader)
+	if err == nil {
+		err = writeState(writer)
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		file.Close()           // nolint: errcheck
+		os.Remove(file.Name()) // nolint: errcheck
+
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// it makes the replacing of the file durable
+func syncDirectory(path string) error {
+	directory, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer directory.Close() // nolint: errcheck
+
+	return directory.Sync()
+}
@@ -0,0 +1,50 @@
+package cache
+
+// FsyncPolicy ...
+//
+// It defines when records of the AOF are flushed to a disk. In any case,
+// a record is written to the file on each change, so a crash of the process
+// alone doesn't lose it.
+//
+type FsyncPolicy int
+
+// ...
+const (
+	// FsyncPolicyEverySecond means flushing once a second in background,
+	// so changes of the last second can be lost on a crash of the system
+	FsyncPolicyEverySecond FsyncPolicy = iota
+	// FsyncPolicyAlways means flushing on each change before the changing
+	// operation returns; it's the safest and the slowest policy
+	FsyncPolicyAlways
+	// FsyncPolicyNever means leaving flushing to the operating system
+	FsyncPolicyNever
+)
+
+type aofConfig struct {
+	fsyncPolicy    FsyncPolicy
+	isStrictReplay bool
+}
+
+// AOFOption ...
+type AOFOption func(config *aofConfig)
+
+// AOFWithFsyncPolicy ...
+//
+// Default: FsyncPolicyEverySecond.
+//
+func AOFWithFsyncPolicy(fsyncPolicy FsyncPolicy) AOFOption {
+	return func(config *aofConfig) {
+		config.fsyncPolicy = fsyncPolicy
+	}
+}
+
+// AOFWithStrictReplay ...
+//
+// It makes the Cache.ReplayAOF() method return ErrCorruptedAOFTail instead
+// of truncating of a truncated or corrupted tail of the AOF.
+//
+func AOFWithStrictReplay() AOFOption {
+	return func(config *aofConfig) {
+		config.isStrictReplay = true
+	}
+}
@@ -0,0 +1,674 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_AOF(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		fsyncPolicy FsyncPolicy
+	}{
+		{
+			name:        "FsyncPolicyEverySecond",
+			fsyncPolicy: FsyncPolicyEverySecond,
+		},
+		{
+			name:        "FsyncPolicyAlways",
+			fsyncPolicy: FsyncPolicyAlways,
+		},
+		{
+			name:        "FsyncPolicyNever",
+			fsyncPolicy: FsyncPolicyNever,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			path := filepath.Join(test.TempDir(), "cache.aof")
+			codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+			aof, err := OpenAOF(path, codec, AOFWithFsyncPolicy(data.fsyncPolicy))
+			require.NoError(test, err)
+
+			cache := NewCache(WithClock(clock), WithAOF(aof))
+			cache.Set(IntKey(1), "one", 0)
+			cache.Set(IntKey(2), "two", time.Minute)
+			cache.Expire(IntKey(2), time.Hour) // nolint: errcheck
+			cache.Set(IntKey(3), "three", 0)
+			cache.Delete(IntKey(3))
+			cache.SetWithOptions(
+				IntKey(4),
+				"four",
+				time.Minute,
+				ValueWithCost(23),
+				ValueWithTags("product:23"),
+			)
+			cache.Set(IntKey(5), "five", 0)
+			cache.SetNegative(IntKey(5), 0)
+			cache.SetMany(
+				[]Entry{{Key: IntKey(6), Data: "six"}, {Key: IntKey(7), Data: "seven"}},
+				0,
+			)
+			cache.DeleteMany([]hashmap.Key{IntKey(7)})
+			cache.IncrBy(IntKey(8), 23, 0) // nolint: errcheck
+			cache.IncrBy(IntKey(8), 42, 0) // nolint: errcheck
+
+			orders := cache.Namespace("orders")
+			orders.Set(IntKey(9), "nine", 0)
+			orders.Set(IntKey(10), "ten", 0)
+			orders.Delete(IntKey(10))
+			orders.Namespace("archive").Set(IntKey(11), "eleven", 0)
+			require.NoError(test, aof.Close())
+
+			aof, err = OpenAOF(path, codec)
+			require.NoError(test, err)
+			defer aof.Close() // nolint: errcheck
+
+			restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+			err = restoredCache.ReplayAOF()
+			require.NoError(test, err)
+
+			assert.Equal(test, []int{1, 2, 4, 6, 8}, iteratedIntKeys(restoredCache))
+			assert.Equal(test, int64(23), restoredCache.Cost())
+
+			gotCounter, err := restoredCache.Get(IntKey(8))
+			assert.Equal(test, int64(65), gotCounter)
+			assert.NoError(test, err)
+
+			gotTTL, err := restoredCache.TTL(IntKey(2))
+			assert.Equal(test, time.Hour, gotTTL)
+			assert.NoError(test, err)
+
+			_, err = restoredCache.Get(IntKey(5))
+			assert.Equal(test, ErrKeyMissed, err)
+
+			assert.Equal(test, 1, restoredCache.InvalidateTag("product:23"))
+
+			restoredOrders := restoredCache.Namespace("orders")
+			assert.Equal(test, []int{9}, iteratedIntKeys(restoredOrders))
+			assert.Equal(
+				test,
+				[]int{11},
+				iteratedIntKeys(restoredOrders.Namespace("archive")),
+			)
+		})
+	}
+}
+
+func TestCache_ReplayAOF_withClear(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), "two", 0)
+	cache.Namespace("orders").Set(IntKey(4), "four", 0)
+	cache.Clear()
+	cache.Set(IntKey(3), "three", 0)
+	cache.Namespace("orders").Set(IntKey(5), "five", 0)
+	cache.Namespace("products").Set(IntKey(6), "six", 0)
+	cache.Namespace("products").Clear()
+	cache.Namespace("products").Set(IntKey(7), "seven", 0)
+	require.NoError(test, aof.Close())
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{3}, iteratedIntKeys(restoredCache))
+	assert.Equal(test, 1, restoredCache.Len())
+
+	// clearing of the cache and of a namespace doesn't affect each other
+	assert.Equal(
+		test,
+		[]int{4, 5},
+		iteratedIntKeys(restoredCache.Namespace("orders")),
+	)
+	assert.Equal(
+		test,
+		[]int{7},
+		iteratedIntKeys(restoredCache.Namespace("products")),
+	)
+}
+
+func TestCache_ReplayAOF_withExpiredValues(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	currentTime := clock()
+	cache := NewCache(
+		WithClock(func() time.Time { return currentTime }),
+		WithAOF(aof),
+	)
+	cache.Set(IntKey(1), "one", time.Minute)
+	cache.Set(IntKey(2), "two", time.Hour)
+	cache.Set(IntKey(3), "three", 0)
+	cache.Expire(IntKey(3), time.Minute) // nolint: errcheck
+
+	// deletion of an expired value isn't logged
+	currentTime = currentTime.Add(2 * time.Minute)
+	sizeBefore := fileSize(test, path)
+	cache.GetWithGC(IntKey(1)) // nolint: errcheck
+	assert.Equal(test, 2, cache.Len())
+	assert.Equal(test, sizeBefore, fileSize(test, path))
+	require.NoError(test, aof.Close())
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(
+		WithClock(func() time.Time { return currentTime }),
+		WithAOF(aof),
+	)
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{2}, iteratedIntKeys(restoredCache))
+	assert.Equal(test, 1, restoredCache.Len())
+}
+
+func TestCache_ReplayAOF_withIdleTTL(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	currentTime := clock()
+	cache := NewCache(
+		WithClock(func() time.Time { return currentTime }),
+		WithAOF(aof),
+	)
+	cache.SetWithOptions(IntKey(1), "one", 0, ValueWithIdleTTL(time.Minute))
+	cache.SetWithOptions(IntKey(2), "two", 0, ValueWithIdleTTL(time.Minute))
+
+	// accesses aren't logged
+	currentTime = currentTime.Add(50 * time.Second)
+	sizeBefore := fileSize(test, path)
+	cache.Get(IntKey(1))   // nolint: errcheck
+	cache.Touch(IntKey(2)) // nolint: errcheck
+	assert.Equal(test, sizeBefore, fileSize(test, path))
+	require.NoError(test, aof.Close())
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	// the values were accessed, so they should survive the restart
+	currentTime = currentTime.Add(40 * time.Second)
+	restoredCache := NewCache(
+		WithClock(func() time.Time { return currentTime }),
+		WithAOF(aof),
+	)
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{1, 2}, iteratedIntKeys(restoredCache))
+
+	// the expiration on idleness is restarted on the replay
+	gotTTL, err := restoredCache.TTL(IntKey(1))
+	assert.Equal(test, time.Minute, gotTTL)
+	assert.NoError(test, err)
+}
+
+func TestCache_ReplayAOF_withLimits(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithMaxSize(2), WithAOF(aof))
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), "two", 0)
+	cache.Get(IntKey(1)) // nolint: errcheck
+	cache.Set(IntKey(3), "three", 0)
+	require.NoError(test, aof.Close())
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(WithClock(clock), WithMaxSize(2), WithAOF(aof))
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	// the eviction is replayed as deletion, regardless of the access
+	assert.Equal(test, []int{1, 3}, iteratedIntKeys(restoredCache))
+}
+
+func TestCache_ReplayAOF_withCorruptedTail(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(23), "one", 0)
+	firstRecordEnd := fileSize(test, path)
+	cache.Set(IntKey(42), "two", 0)
+	require.NoError(test, aof.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(test, err)
+
+	for _, data := range []struct {
+		name       string
+		corrupt    func(content []byte) []byte
+		wantKeys   []int
+		wantLength int64
+	}{
+		{
+			name: "truncated record",
+			corrupt: func(content []byte) []byte {
+				return content[:len(content)-2]
+			},
+			wantKeys:   []int{23},
+			wantLength: firstRecordEnd,
+		},
+		{
+			name: "corrupted record",
+			corrupt: func(content []byte) []byte {
+				content[len(content)-6] ^= 0xff
+				return content
+			},
+			wantKeys:   []int{23},
+			wantLength: firstRecordEnd,
+		},
+		{
+			name: "corrupted length of a record",
+			corrupt: func(content []byte) []byte {
+				content[firstRecordEnd] ^= 0xff
+				return content
+			},
+			wantKeys:   []int{23},
+			wantLength: firstRecordEnd,
+		},
+		{
+			name: "garbage after records",
+			corrupt: func(content []byte) []byte {
+				return append(content, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff)
+			},
+			wantKeys:   []int{23, 42},
+			wantLength: int64(len(content)),
+		},
+		{
+			name: "truncated length of a record",
+			corrupt: func(content []byte) []byte {
+				return append(content, 0x80)
+			},
+			wantKeys:   []int{23, 42},
+			wantLength: int64(len(content)),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			corruptedContent := data.corrupt(append([]byte(nil), content...))
+
+			for _, strictData := range []struct {
+				name       string
+				options    []AOFOption
+				wantErr    error
+				wantLength int64
+			}{
+				{
+					name:       "with truncation",
+					options:    nil,
+					wantErr:    nil,
+					wantLength: data.wantLength,
+				},
+				{
+					name:       "with the strict replay",
+					options:    []AOFOption{AOFWithStrictReplay()},
+					wantErr:    ErrCorruptedAOFTail,
+					wantLength: int64(len(corruptedContent)),
+				},
+			} {
+				test.Run(strictData.name, func(test *testing.T) {
+					path := filepath.Join(test.TempDir(), "cache.aof")
+					err := os.WriteFile(path, corruptedContent, 0o600)
+					require.NoError(test, err)
+
+					aof, err := OpenAOF(path, codec, strictData.options...)
+					require.NoError(test, err)
+					defer aof.Close() // nolint: errcheck
+
+					restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+					err = restoredCache.ReplayAOF()
+
+					assert.ErrorIs(test, err, strictData.wantErr)
+					assert.Equal(test, data.wantKeys, iteratedIntKeys(restoredCache))
+					assert.Equal(test, strictData.wantLength, fileSize(test, path))
+				})
+			}
+		})
+	}
+}
+
+func TestCache_ReplayAOF_afterTruncation(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(23), "one", 0)
+	require.NoError(test, aof.Close())
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(test, err)
+	_, err = file.Write([]byte{0x23, 0x42})
+	require.NoError(test, err)
+	require.NoError(test, file.Close())
+
+	for _, wantKeys := range [][]int{{23, 42}, {23, 42, 100}} {
+		aof, err = OpenAOF(path, codec)
+		require.NoError(test, err)
+
+		restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+		err = restoredCache.ReplayAOF()
+		require.NoError(test, err)
+
+		// new records are appended after the valid ones
+		restoredCache.Set(IntKey(wantKeys[len(wantKeys)-1]), "data", 0)
+		require.NoError(test, aof.Close())
+
+		assert.Equal(test, wantKeys, iteratedIntKeys(restoredCache))
+	}
+
+	aof, err = OpenAOF(path, codec, AOFWithStrictReplay())
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{23, 42, 100}, iteratedIntKeys(restoredCache))
+}
+
+func TestCache_RewriteAOF(test *testing.T) {
+	directory := test.TempDir()
+	path := filepath.Join(directory, "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	for index := 0; index < 100; index++ {
+		cache.Set(IntKey(index%10), index, 0)
+	}
+	cache.Delete(IntKey(9))
+	cache.Set(IntKey(10), "expired", -time.Minute)
+	cache.SetNegative(IntKey(11), 0)
+	cache.Namespace("orders").Set(IntKey(12), "twelve", 0)
+	cache.Namespace("orders").Namespace("archive").Set(IntKey(13), "thirteen", 0)
+
+	sizeBefore := fileSize(test, path)
+	err = cache.RewriteAOF()
+	require.NoError(test, err)
+
+	assert.Less(test, fileSize(test, path), sizeBefore)
+
+	cache.Set(IntKey(23), 23, 0)
+	require.NoError(test, aof.Close())
+
+	// the temporary file is renamed
+	entries, err := os.ReadDir(directory)
+	require.NoError(test, err)
+	require.Len(test, entries, 1)
+	assert.Equal(test, "cache.aof", entries[0].Name())
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	assert.Equal(
+		test,
+		[]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 23},
+		iteratedIntKeys(restoredCache),
+	)
+	for index := 0; index < 9; index++ {
+		data, err := restoredCache.Get(IntKey(index))
+
+		assert.Equal(test, 90+index, data)
+		assert.NoError(test, err)
+	}
+
+	restoredOrders := restoredCache.Namespace("orders")
+	assert.Equal(test, []int{12}, iteratedIntKeys(restoredOrders))
+	assert.Equal(
+		test,
+		[]int{13},
+		iteratedIntKeys(restoredOrders.Namespace("archive")),
+	)
+}
+
+func TestCache_RewriteAOF_withConcurrentChanges(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	hook := new(atomic.Pointer[func()])
+	codec := SnapshotCodec{
+		Key:  GobCodec{},
+		Data: hookingCodec{Codec: GobCodec{}, hook: hook},
+	}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), "two", 0)
+
+	// the hook is called on encoding of the first value during the rewrite
+	var concurrentRewriteErr error
+	changeConcurrently := func() {
+		cache.Set(IntKey(1), "changed during the rewrite", 0)
+		cache.Delete(IntKey(2))
+		cache.Set(IntKey(3), "set during the rewrite", 0)
+
+		concurrentRewriteErr = cache.RewriteAOF()
+	}
+	hook.Store(&changeConcurrently)
+
+	err = cache.RewriteAOF()
+	require.NoError(test, err)
+
+	assert.Equal(test, ErrAOFRewriteInProgress, concurrentRewriteErr)
+	require.NoError(test, aof.Close())
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{1, 3}, iteratedIntKeys(restoredCache))
+
+	data, err := restoredCache.Get(IntKey(1))
+	assert.Equal(test, "changed during the rewrite", data)
+	assert.NoError(test, err)
+}
+
+func TestCache_AOF_withCodecError(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: NewJSONCodec(nil)}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), make(chan int), 0)
+	cache.Set(IntKey(3), "three", 0)
+
+	const wantErr = "unable to encode the data: json: unsupported type: chan int"
+	assert.EqualError(test, aof.Sync(), wantErr)
+	assert.EqualError(test, cache.RewriteAOF(), wantErr)
+	assert.EqualError(test, aof.Close(), wantErr)
+
+	aof, err = OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	restoredCache := NewCache(WithClock(clock), WithAOF(aof))
+	err = restoredCache.ReplayAOF()
+	require.NoError(test, err)
+
+	// the logging is stopped on the error
+	assert.Equal(test, []int{1}, iteratedIntKeys(restoredCache))
+}
+
+func TestCache_AOF_withEverySecondSyncing(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(23), "data", 0)
+
+	require.Eventually(test, func() bool {
+		aof.lock.Lock()
+		defer aof.lock.Unlock()
+
+		return !aof.isDirty
+	}, 3*aofSyncPeriod, 10*time.Millisecond)
+}
+
+func TestCache_AOF_withoutAOF(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+
+	assert.Equal(test, ErrAOFNotSet, cache.ReplayAOF())
+	assert.Equal(test, ErrAOFNotSet, cache.RewriteAOF())
+	assert.Equal(test, ErrAOFNotSet, cache.Namespace("orders").ReplayAOF())
+}
+
+func TestCache_AOF_withNamespace(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+	defer aof.Close() // nolint: errcheck
+
+	// the AOF is replayed and rewritten by the cache as a whole
+	orders := NewCache(WithClock(clock), WithAOF(aof)).Namespace("orders")
+	assert.Equal(test, ErrAOFNotSet, orders.ReplayAOF())
+	assert.Equal(test, ErrAOFNotSet, orders.RewriteAOF())
+}
+
+func TestCache_AOF_withClosedAOF(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "cache.aof")
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	aof, err := OpenAOF(path, codec)
+	require.NoError(test, err)
+	require.NoError(test, aof.Close())
+
+	cache := NewCache(WithClock(clock), WithAOF(aof))
+	cache.Set(IntKey(23), "data", 0)
+
+	assert.Equal(test, ErrAOFClosed, cache.ReplayAOF())
+	assert.Equal(test, ErrAOFClosed, cache.RewriteAOF())
+	assert.Equal(test, ErrAOFClosed, aof.Sync())
+	assert.Equal(test, ErrAOFClosed, aof.Close())
+	assert.Equal(test, int64(len(aofHeader)), fileSize(test, path))
+}
+
+func TestOpenAOF_withError(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		content []byte
+		wantErr error
+	}{
+		{
+			name:    "invalid magic",
+			content: []byte("not an AOF at all"),
+			wantErr: ErrInvalidAOF,
+		},
+		{
+			name:    "truncated header",
+			content: []byte(aofMagic[:5]),
+			wantErr: ErrInvalidAOF,
+		},
+		{
+			name:    "truncated version",
+			content: append([]byte(aofMagic), 0x80),
+			wantErr: ErrInvalidAOF,
+		},
+		{
+			name:    "unsupported version",
+			content: append([]byte(aofMagic), 2),
+			wantErr: ErrUnsupportedAOFVersion,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			path := filepath.Join(test.TempDir(), "cache.aof")
+			err := os.WriteFile(path, data.content, 0o600)
+			require.NoError(test, err)
+
+			aof, err := OpenAOF(path, SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}})
+
+			assert.Nil(test, aof)
+			assert.ErrorIs(test, err, data.wantErr)
+		})
+	}
+}
+
+func TestOpenAOF_withMissedDirectory(test *testing.T) {
+	path := filepath.Join(test.TempDir(), "missed", "cache.aof")
+	aof, err := OpenAOF(path, SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}})
+
+	assert.Nil(test, aof)
+	assert.ErrorIs(test, err, os.ErrNotExist)
+}
+
+// it calls the stored hook once on encoding
+type hookingCodec struct {
+	Codec
+
+	hook *atomic.Pointer[func()]
+}
+
+func (codec hookingCodec) Encode(value interface{}) ([]byte, error) {
+	if hook := codec.hook.Swap(nil); hook != nil {
+		(*hook)()
+	}
+
+	return codec.Codec.Encode(value)
+}
+
+func fileSize(test *testing.T, path string) int64 {
+	info, err := os.Stat(path)
+	require.NoError(test, err)
+
+	return info.Size()
+}
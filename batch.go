@@ -0,0 +1,117 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// Entry ...
+type Entry struct {
+	Key  hashmap.Key
+	Data interface{}
+}
+
+// BatchResult ...
+//
+// The error can be ErrKeyMissed, ErrKeyExpired, ErrKeyStale (the data is set
+// for it) or an error of a negative value.
+//
+type BatchResult struct {
+	Data interface{}
+	Err  error
+}
+
+// GetMany ...
+//
+// It's the same as the Get() method, but for several keys at once.
+// Expiration of the values, including early one (see the
+// WithEarlyRecomputation() option), is checked against the clock read once
+// for all the keys. Results correspond to the keys by indices.
+//
+// If the storage implements the BatchStorage interface, the keys are got
+// in bulk.
+//
+func (cache Cache) GetMany(keys []hashmap.Key) []BatchResult {
+	// the original cache is still used for accesses, since they can start
+	// revalidation in background, which shouldn't see the frozen clock
+	batchCache := cache.withCurrentTime(cache.clock())
+
+	results := make([]BatchResult, len(keys))
+	data, oks := getMany(cache.storage, keys)
+	for index, key := range keys {
+		if !oks[index] || batchCache.isCleared(data[index].(models.Value)) {
+			results[index].Err = ErrKeyMissed
+			cache.stats.addGetting(ErrKeyMissed)
+
+			continue
+		}
+
+		value := data[index].(models.Value)
+		if value.IsExpired(batchCache.clock) ||
+			value.IsExpiredEarly(
+				batchCache.clock,
+				cache.earlyRecomputationBeta,
+				cache.random,
+			) {
+			results[index].Err = ErrKeyExpired
+			cache.stats.addGetting(ErrKeyExpired)
+
+			continue
+		}
+
+		results[index].Data, results[index].Err =
+			cache.access(key, value, cache.revalidator)
+		cache.stats.addGetting(results[index].Err)
+	}
+
+	return results
+}
+
+// SetMany ...
+//
+// It's the same as the SetWithOptions() method, but for several entries
+// at once. The clock is read once for all the entries, so all of them get
+// the same expiration time. If keys of entries are equal, the last entry wins.
+//
+// The batch is atomic for each key, but not for the entries as a whole.
+// If the storage implements the BatchStorage interface, the entries are set
+// in bulk.
+//
+func (cache Cache) SetMany(
+	entries []Entry,
+	ttl time.Duration,
+	options ...ValueOption,
+) {
+	cache = cache.withCurrentTime(cache.clock())
+
+	keys := make([]hashmap.Key, len(entries))
+	for index, entry := range entries {
+		keys[index] = entry.Key
+	}
+
+	cache.runTransactions(keys, func(index int, transaction *keyTransaction) {
+		entry := entries[index]
+		transaction.set(cache.newValue(entry.Key, entry.Data, ttl, options))
+	})
+}
+
+// DeleteMany ...
+//
+// It's the same as the Delete() method, but for several keys at once.
+//
+// If the storage implements the BatchStorage interface, the keys are deleted
+// in bulk.
+//
+func (cache Cache) DeleteMany(keys []hashmap.Key) {
+	cache.runTransactions(keys, func(index int, transaction *keyTransaction) {
+		transaction.delete(RemovalReasonDeleted)
+	})
+}
+
+// it's used by batch operations to read the clock once
+func (cache Cache) withCurrentTime(currentTime time.Time) Cache {
+	cache.clock = func() time.Time { return currentTime }
+	return cache
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+//go:generate mockery -name=BatchStorage -inpkg -case=underscore -testonly
+
+// BatchStorage ...
+//
+// It's an optional extension of the hashmap.Storage interface. If the storage
+// implements it, batch operations of the cache process keys in bulk,
+// e.g., so that the storage can lock each of its segments once.
+//
+// Results of the GetMany() method should correspond to the keys by indices.
+// The SetMany() method should set the data by the same indices.
+//
+type BatchStorage interface {
+	hashmap.Storage
+
+	GetMany(keys []hashmap.Key) (data []interface{}, oks []bool)
+	SetMany(keys []hashmap.Key, data []interface{})
+	DeleteMany(keys []hashmap.Key)
+}
+
+func getMany(
+	storage hashmap.Storage,
+	keys []hashmap.Key,
+) (data []interface{}, oks []bool) {
+	if batchStorage, ok := storage.(BatchStorage); ok {
+		return batchStorage.GetMany(keys)
+	}
+
+	data, oks = make([]interface{}, len(keys)), make([]bool, len(keys))
+	for index, key := range keys {
+		data[index], oks[index] = storage.Get(key)
+	}
+
+	return data, oks
+}
+
+func setMany(storage hashmap.Storage, keys []hashmap.Key, data []interface{}) {
+	if batchStorage, ok := storage.(BatchStorage); ok {
+		batchStorage.SetMany(keys, data)
+		return
+	}
+
+	for index, key := range keys {
+		storage.Set(key, data[index])
+	}
+}
+
+func deleteMany(storage hashmap.Storage, keys []hashmap.Key) {
+	if batchStorage, ok := storage.(BatchStorage); ok {
+		batchStorage.DeleteMany(keys)
+		return
+	}
+
+	for _, key := range keys {
+		storage.Delete(key)
+	}
+}
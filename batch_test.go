@@ -0,0 +1,319 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_GetMany(test *testing.T) {
+	type fields struct {
+		storage hashmap.Storage
+	}
+	type args struct {
+		keys []hashmap.Key
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		args   args
+		want   []BatchResult
+	}{
+		{
+			name: "without keys",
+			fields: fields{
+				storage: new(MockStorage),
+			},
+			args: args{
+				keys: nil,
+			},
+			want: []BatchResult{},
+		},
+		{
+			name: "with a storage",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(1)).Return(nil, false)
+					storage.
+						On("Get", IntKey(2)).
+						Return(models.Value{Data: "data #2", ExpirationTime: clock().Add(-time.Second)}, true)
+					storage.
+						On("Get", IntKey(3)).
+						Return(models.Value{Data: "data #3", ExpirationTime: clock().Add(time.Second)}, true)
+
+					return storage
+				}(),
+			},
+			args: args{
+				keys: []hashmap.Key{IntKey(1), IntKey(2), IntKey(3)},
+			},
+			want: []BatchResult{
+				{Data: nil, Err: ErrKeyMissed},
+				{Data: nil, Err: ErrKeyExpired},
+				{Data: "data #3", Err: nil},
+			},
+		},
+		{
+			name: "with a batch storage",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockBatchStorage)
+					storage.
+						On("GetMany", []hashmap.Key{IntKey(1), IntKey(2), IntKey(3)}).
+						Return(
+							[]interface{}{
+								nil,
+								models.Value{Data: "data #2", ExpirationTime: clock().Add(-time.Second)},
+								models.Value{Data: "data #3", ExpirationTime: clock().Add(time.Second)},
+							},
+							[]bool{false, true, true},
+						)
+
+					return storage
+				}(),
+			},
+			args: args{
+				keys: []hashmap.Key{IntKey(1), IntKey(2), IntKey(3)},
+			},
+			want: []BatchResult{
+				{Data: nil, Err: ErrKeyMissed},
+				{Data: nil, Err: ErrKeyExpired},
+				{Data: "data #3", Err: nil},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithStorage(data.fields.storage), WithClock(clock))
+			got := cache.GetMany(data.args.keys)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage)
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestCache_GetMany_withEarlyRecomputation(test *testing.T) {
+	currentTime := clock()
+	cache := NewCache(
+		WithClock(func() time.Time { return currentTime }),
+		// it makes the gap before the expiration time about 69 seconds
+		WithRandom(func() float64 { return 0.999 }),
+		WithEarlyRecomputation(1),
+	)
+	cache.SetWithOptions(
+		IntKey(1),
+		"data #1",
+		time.Minute,
+		ValueWithRecomputeCost(10*time.Second),
+	)
+	cache.Set(IntKey(2), "data #2", time.Minute)
+
+	currentTime = currentTime.Add(30 * time.Second)
+	got := cache.GetMany([]hashmap.Key{IntKey(1), IntKey(2)})
+
+	assert.Equal(
+		test,
+		[]BatchResult{
+			{Data: nil, Err: ErrKeyExpired},
+			{Data: "data #2", Err: nil},
+		},
+		got,
+	)
+	assert.Equal(test, int64(1), cache.Stats().ExpiredMisses)
+}
+
+func TestCache_SetMany(test *testing.T) {
+	type fields struct {
+		storage hashmap.Storage
+	}
+	type args struct {
+		entries []Entry
+		ttl     time.Duration
+	}
+
+	for _, data := range []struct {
+		name     string
+		fields   fields
+		args     args
+		wantSize int64
+	}{
+		{
+			name: "with a storage",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(1)).Return(nil, false)
+					storage.
+						On("Get", IntKey(2)).
+						Return(models.Value{Data: "data #0"}, true)
+					storage.On("Set", IntKey(1), models.Value{
+						Data:           "data #1",
+						ExpirationTime: clock().Add(time.Second),
+					})
+					storage.On("Set", IntKey(2), models.Value{
+						Data:           "data #3",
+						ExpirationTime: clock().Add(time.Second),
+					})
+
+					return storage
+				}(),
+			},
+			args: args{
+				entries: []Entry{
+					{Key: IntKey(1), Data: "data #1"},
+					{Key: IntKey(2), Data: "data #2"},
+					{Key: IntKey(2), Data: "data #3"},
+				},
+				ttl: time.Second,
+			},
+			wantSize: 1,
+		},
+		{
+			name: "with a batch storage",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockBatchStorage)
+					storage.
+						On("GetMany", []hashmap.Key{IntKey(1), IntKey(2)}).
+						Return([]interface{}{nil, models.Value{Data: "data #0"}}, []bool{false, true})
+					storage.On(
+						"SetMany",
+						[]hashmap.Key{IntKey(1), IntKey(2)},
+						[]interface{}{
+							models.Value{Data: "data #1", ExpirationTime: clock().Add(time.Second)},
+							models.Value{Data: "data #3", ExpirationTime: clock().Add(time.Second)},
+						},
+					)
+
+					return storage
+				}(),
+			},
+			args: args{
+				entries: []Entry{
+					{Key: IntKey(1), Data: "data #1"},
+					{Key: IntKey(2), Data: "data #2"},
+					{Key: IntKey(2), Data: "data #3"},
+				},
+				ttl: time.Second,
+			},
+			wantSize: 1,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var clockCallCount int
+			cache := NewCache(
+				WithStorage(data.fields.storage),
+				WithClock(func() time.Time {
+					clockCallCount++
+					return clock()
+				}),
+			)
+			cache.SetMany(data.args.entries, data.args.ttl)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage)
+			assert.Equal(test, 1, clockCallCount)
+			assert.Equal(test, data.wantSize, cache.size.Load())
+		})
+	}
+}
+
+func TestCache_DeleteMany(test *testing.T) {
+	type fields struct {
+		storage hashmap.Storage
+	}
+	type args struct {
+		keys []hashmap.Key
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		args   args
+	}{
+		{
+			name: "without keys",
+			fields: fields{
+				storage: new(MockBatchStorage),
+			},
+			args: args{
+				keys: nil,
+			},
+		},
+		{
+			name: "with a storage",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(1)).Return(nil, false)
+					storage.On("Get", IntKey(2)).Return(models.Value{Data: "data"}, true)
+					storage.On("Delete", IntKey(2)).Once()
+
+					return storage
+				}(),
+			},
+			args: args{
+				keys: []hashmap.Key{IntKey(1), IntKey(2), IntKey(2)},
+			},
+		},
+		{
+			name: "with a batch storage",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockBatchStorage)
+					storage.
+						On("GetMany", []hashmap.Key{IntKey(1), IntKey(2)}).
+						Return([]interface{}{nil, models.Value{Data: "data"}}, []bool{false, true})
+					storage.On("DeleteMany", []hashmap.Key{IntKey(2)})
+
+					return storage
+				}(),
+			},
+			args: args{
+				keys: []hashmap.Key{IntKey(1), IntKey(2), IntKey(2)},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithStorage(data.fields.storage), WithClock(clock))
+			cache.DeleteMany(data.args.keys)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage)
+		})
+	}
+}
+
+func TestCache_batchOperations(test *testing.T) {
+	cache := NewCache(WithClock(clock), WithMaxSize(2))
+	cache.SetMany(
+		[]Entry{
+			{Key: IntKey(1), Data: "data #1"},
+			{Key: IntKey(2), Data: "data #2"},
+			{Key: IntKey(3), Data: "data #3"},
+		},
+		0,
+	)
+	gotResults := cache.GetMany([]hashmap.Key{IntKey(1), IntKey(2), IntKey(3)})
+
+	wantResults := []BatchResult{
+		{Data: nil, Err: ErrKeyMissed},
+		{Data: "data #2", Err: nil},
+		{Data: "data #3", Err: nil},
+	}
+	assert.Equal(test, wantResults, gotResults)
+
+	cache.DeleteMany([]hashmap.Key{IntKey(2), IntKey(3)})
+	gotResults = cache.GetMany([]hashmap.Key{IntKey(2), IntKey(3)})
+
+	wantResults = []BatchResult{
+		{Data: nil, Err: ErrKeyMissed},
+		{Data: nil, Err: ErrKeyMissed},
+	}
+	assert.Equal(test, wantResults, gotResults)
+	assert.Equal(test, int64(0), cache.size.Load())
+}
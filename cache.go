@@ -3,8 +3,11 @@ package cache
 import (
 	"context"
 	"errors"
+	"math/rand"
+	"sync/atomic"
 	"time"
 
+	"github.com/thewizardplusplus/go-cache/eviction"
 	"github.com/thewizardplusplus/go-cache/gc"
 	"github.com/thewizardplusplus/go-cache/models"
 	hashmap "github.com/thewizardplusplus/go-hashmap"
@@ -14,12 +17,62 @@ import (
 var (
 	ErrKeyMissed  = errors.New("key missed")
 	ErrKeyExpired = errors.New("key expired")
+
+	// it's returned only internally instead of ErrKeyExpired
+	errKeyExpiredEarly = errors.New("key expired early")
+)
+
+// Loader ...
+//
+// It should return the data for the key and its time to live.
+// Zero time to live means infinite one.
+//
+type Loader func(ctx context.Context, key hashmap.Key) (
+	data interface{},
+	ttl time.Duration,
+	err error,
 )
 
+// Weigher ...
+//
+// It should return a cost of the data, e.g., its size in bytes.
+//
+type Weigher func(key hashmap.Key, data interface{}) int64
+
 // Cache ...
 type Cache struct {
-	storage hashmap.Storage
-	clock   models.Clock
+	storage        hashmap.Storage
+	clock          models.Clock
+	maxSize        int
+	weigher        Weigher
+	maxCost        int64
+	evictionPolicy eviction.Policy
+
+	removalListener       RemovalListener
+	isRemovalListenerSync bool
+
+	random    models.Random
+	ttlJitter ttlJitter
+
+	earlyRecomputationBeta float64
+	staleTTL               time.Duration
+	revalidator            Loader
+
+	aof *AOF
+	// it's set for views of namespaces, so that their changes are logged
+	// with it; nested namespaces have several names
+	namespacePath []string
+
+	loads  *loadGroup
+	locks  *keyLocks
+	events *eventHub
+	stats  *statsCounters
+	tags   *tagIndex
+	size   *atomic.Int64
+	cost   *atomic.Int64
+
+	generation *atomic.Uint64
+	namespaces *namespaceRegistry
 }
 
 // NewCache ...
@@ -28,10 +81,25 @@ func NewCache(options ...Option) Cache {
 	cache := Cache{
 		storage: hashmap.NewConcurrentHashMap(),
 		clock:   time.Now,
+		random:  rand.Float64,
+
+		loads:  newLoadGroup(),
+		locks:  newKeyLocks(),
+		events: newEventHub(),
+		stats:  newStatsCounters(),
+		tags:   newTagIndex(),
+		size:   new(atomic.Int64),
+		cost:   new(atomic.Int64),
+
+		generation: new(atomic.Uint64),
+		namespaces: newNamespaceRegistry(),
 	}
 	for _, option := range options {
 		option(&cache)
 	}
+	if cache.isBounded() && cache.evictionPolicy == nil {
+		cache.evictionPolicy = eviction.NewLRU()
+	}
 
 	return cache
 }
@@ -42,29 +110,55 @@ func NewCache(options ...Option) Cache {
 //
 func NewCacheWithGC(ctx context.Context, options ...OptionWithGC) Cache {
 	config := newConfigWithGC(options)
+	removalListenerOption := WithRemovalListener
+	if config.isRemovalListenerSync {
+		removalListenerOption = WithSyncRemovalListener
+	}
 
-	gcInstance := config.gcFactory(config.storage, config.clock)
-	go gc.Run(ctx, gcInstance, config.gcPeriod)
+	cache := NewCache(
+		WithStorage(config.storage),
+		WithClock(config.clock),
+		WithMaxSize(config.maxSize),
+		WithWeigher(config.weigher),
+		WithMaxCost(config.maxCost),
+		WithEvictionPolicy(config.evictionPolicy),
+		removalListenerOption(config.removalListener),
+		WithRandom(config.random),
+		WithTTLJitter(config.ttlJitter.percent),
+		WithTTLJitterRange(config.ttlJitter.minJitter, config.ttlJitter.maxJitter),
+		WithEarlyRecomputation(config.earlyRecomputationBeta),
+		WithStaleTTL(config.staleTTL),
+		WithRevalidator(config.revalidator),
+		WithAOF(config.aof),
+	)
 
-	return NewCache(WithStorage(config.storage), WithClock(config.clock))
+	gcInstance :=
+		config.gcFactory(cache.GCStorage(), config.clock)
+	go gc.Run(
+		ctx,
+		gcInstance,
+		config.gcPeriod,
+		gc.RunWithReportHandler(config.gcReportHandler),
+	)
+
+	return cache
 }
 
 // Get ...
 //
-// The error can be ErrKeyMissed or ErrKeyExpired only.
+// The error can be ErrKeyMissed, ErrKeyExpired or an error of a negative
+// value (see the SetNegative() and SetError() methods).
+//
+// If early recomputation is enabled, a live value can be considered expired
+// before its expiration time (see the WithEarlyRecomputation() option).
+//
+// For a stale value (see the ValueWithStaleTTL() option), it returns its data
+// together with ErrKeyStale and starts its revalidation in background,
+// if the revalidator is set (see the WithRevalidator() option).
 //
 func (cache Cache) Get(key hashmap.Key) (data interface{}, err error) {
-	data, ok := cache.storage.Get(key)
-	if !ok {
-		return nil, ErrKeyMissed
-	}
-
-	value := data.(models.Value)
-	if value.IsExpired(cache.clock) {
-		return nil, ErrKeyExpired
-	}
-
-	return value.Data, nil
+	data, _, err = cache.getWithStats(key, cache.revalidator)
+	return data, err
 }
 
 // GetWithGC ...
@@ -74,8 +168,11 @@ func (cache Cache) Get(key hashmap.Key) (data interface{}, err error) {
 func (cache Cache) GetWithGC(key hashmap.Key) (data interface{}, err error) {
 	data, err = cache.Get(key)
 	if err != nil {
+		if err == ErrKeyStale {
+			return data, err
+		}
 		if err == ErrKeyExpired {
-			cache.storage.Delete(key)
+			cache.deleteExpired(key)
 		}
 
 		return nil, err
@@ -84,6 +181,98 @@ func (cache Cache) GetWithGC(key hashmap.Key) (data interface{}, err error) {
 	return data, nil
 }
 
+// GetIncludingExpired ...
+//
+// It's the same as the Get() method, but for an expired value that isn't yet
+// deleted, it returns its data together with ErrKeyExpired (e.g.,
+// for serving in a degraded mode). It doesn't delete the value.
+//
+// It additionally returns the expiration time of the value, taking into
+// account expiration on idleness. Zero time means infinite time to live.
+// Early recomputation isn't applied.
+//
+func (cache Cache) GetIncludingExpired(key hashmap.Key) (
+	data interface{},
+	expirationTime time.Time,
+	err error,
+) {
+	data, ok := cache.storage.Get(key)
+	if !ok || cache.isCleared(data.(models.Value)) {
+		cache.stats.addGetting(ErrKeyMissed)
+		return nil, time.Time{}, ErrKeyMissed
+	}
+
+	value := data.(models.Value)
+	if value.IsExpired(cache.clock) {
+		cache.stats.addGetting(ErrKeyExpired)
+		return value.Data, effectiveExpirationTime(value), ErrKeyExpired
+	}
+
+	data, err = cache.access(key, value, cache.revalidator)
+	cache.stats.addGetting(err)
+
+	// the access postpones expiration on idleness
+	return data, effectiveExpirationTime(value), err
+}
+
+// GetOrLoad ...
+//
+// If the key is missed or expired, it calls the loader and stores its result.
+// Concurrent calls for the same key share a single loader call and its result,
+// including an error. An error of the loader isn't stored, unless it's
+// the NegativeError one (see the NegativeLoader() function). For a negative
+// value, it returns its error without calling the loader. A panic
+// of the loader is recovered and returned as LoaderPanicError.
+//
+// A duration of the loader call is stored as a recomputation cost
+// of the value, so that the latter can be recomputed before its expiration
+// (see the WithEarlyRecomputation() option).
+//
+// For a stale value (see the ValueWithStaleTTL() option), it returns its data
+// together with ErrKeyStale and starts its revalidation via the loader
+// in background. The revalidation is started once for the value; if it fails,
+// the stale value is served until its expiration.
+//
+// Canceling the context only stops waiting for the calling goroutine;
+// the shared loader call is canceled when all its waiters leave.
+//
+func (cache Cache) GetOrLoad(
+	ctx context.Context,
+	key hashmap.Key,
+	loader Loader,
+) (data interface{}, err error) {
+	data, isExpiredEarly, err := cache.getWithStats(key, loader)
+	if !isAbsent(err) {
+		return data, err
+	}
+
+	return cache.loads.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		// the key could be loaded by a finished concurrent call; a value expired
+		// early is still present, so the check is skipped for it
+		if !isExpiredEarly {
+			if data, err := cache.get(key, nil); !isAbsent(err) {
+				return data, err
+			}
+		}
+
+		startTime := cache.clock()
+		data, ttl, err := loader(ctx, key)
+		options := []ValueOption{ValueWithRecomputeCost(cache.clock().Sub(startTime))}
+		if err != nil {
+			var negativeErr NegativeError
+			if errors.As(err, &negativeErr) {
+				cache.setNegative(key, negativeErr, ttl, options)
+				return nil, negativeErr
+			}
+
+			return nil, err
+		}
+
+		cache.SetWithOptions(key, data, ttl, options...)
+		return data, nil
+	})
+}
+
 // Iterate ...
 //
 // If the handler returns false, iteration is broken.
@@ -102,28 +291,324 @@ func (cache Cache) IterateWithGC(
 	ctx context.Context,
 	handler hashmap.Handler,
 ) bool {
-	return cache.iterateWithExpiredHandler(ctx, handler, cache.storage.Delete)
+	return cache.iterateWithExpiredHandler(ctx, handler, cache.deleteExpired)
 }
 
 // Set ...
 //
 // Zero time to live means infinite one.
 //
+// If the weigher is set, it's used to calculate a cost of the data.
+// Otherwise, the cost is zero.
+//
+// If the maximal size or cost is set and exceeded, it additionally evicts
+// values chosen by the eviction policy. If the latter implements
+// the eviction.Admitter interface, it can reject setting of a missed key
+// into a full cache.
+//
 func (cache Cache) Set(key hashmap.Key, data interface{}, ttl time.Duration) {
-	var expirationTime time.Time
-	if ttl != 0 {
-		expirationTime = cache.clock().Add(ttl)
+	cache.SetWithOptions(key, data, ttl)
+}
+
+// SetWithCost ...
+//
+// It's the same as the Set() method, but it uses the specified cost
+// instead of the weigher.
+//
+// If the maximal cost is set and the specified cost exceeds it,
+// the data isn't set, and the previous data of the key is deleted.
+//
+func (cache Cache) SetWithCost(
+	key hashmap.Key,
+	data interface{},
+	ttl time.Duration,
+	cost int64,
+) {
+	cache.SetWithOptions(key, data, ttl, ValueWithCost(cost))
+}
+
+// SetWithOptions ...
+//
+// It's the same as the Set() method, but it additionally applies
+// the specified options of the value.
+//
+// If the maximal cost is set and a cost of the value exceeds it,
+// the data isn't set, and the previous data of the key is deleted.
+//
+func (cache Cache) SetWithOptions(
+	key hashmap.Key,
+	data interface{},
+	ttl time.Duration,
+	options ...ValueOption,
+) {
+	cache.setValue(key, cache.newValue(key, data, ttl, options))
+}
+
+// Delete ...
+func (cache Cache) Delete(key hashmap.Key) {
+	cache.deleteIf(key, RemovalReasonDeleted, func(value models.Value) bool {
+		return true
+	})
+}
+
+// Cost ...
+//
+// It returns a total cost of values, including expired but not yet deleted
+// ones.
+//
+func (cache Cache) Cost() int64 {
+	return cache.cost.Load()
+}
+
+// Len ...
+//
+// It returns a count of values, including expired but not yet deleted ones.
+// It takes constant time.
+//
+func (cache Cache) Len() int {
+	return int(cache.size.Load())
+}
+
+// LiveLen ...
+//
+// It returns a count of values, excluding expired ones. It iterates over
+// all the values, so it takes linear time.
+//
+func (cache Cache) LiveLen() int {
+	var count int
+	cache.iterateWithExpiredHandler(
+		context.Background(),
+		func(key hashmap.Key, data interface{}) bool {
+			count++
+			return true
+		},
+		func(key hashmap.Key) {},
+	)
+
+	return count
+}
+
+// Clear ...
+//
+// It deletes all the values. For concurrent readers, all the values
+// disappear at once, even though their actual deletion takes linear time.
+// Values set concurrently with the clearing may be kept.
+//
+func (cache Cache) Clear() {
+	// changing of the generation under all the key locks guarantees
+	// that values of the previous generation can't be set after the iteration
+	unlock := cache.locks.lockAll()
+	cache.generation.Add(1)
+	cache.logClearing()
+	unlock()
+
+	cache.storage.Iterate(func(key hashmap.Key, data interface{}) bool {
+		if !isNamespacedKey(key) && cache.isCleared(data.(models.Value)) {
+			cache.deleteIf(key, RemovalReasonCleared, cache.isCleared)
+		}
+
+		return true
+	})
+}
+
+func (cache Cache) getWithStats(key hashmap.Key, revalidator Loader) (
+	data interface{},
+	isExpiredEarly bool,
+	err error,
+) {
+	data, err = cache.get(key, revalidator)
+	if err == errKeyExpiredEarly {
+		isExpiredEarly, err = true, ErrKeyExpired
+	}
+
+	cache.stats.addGetting(err)
+	return data, isExpiredEarly, err
+}
+
+// it doesn't affect statistics; the revalidator is optional
+func (cache Cache) get(
+	key hashmap.Key,
+	revalidator Loader,
+) (data interface{}, err error) {
+	data, ok := cache.storage.Get(key)
+	if !ok || cache.isCleared(data.(models.Value)) {
+		return nil, ErrKeyMissed
+	}
+
+	value := data.(models.Value)
+	if value.IsExpired(cache.clock) {
+		return nil, ErrKeyExpired
+	}
+	if value.IsExpiredEarly(cache.clock, cache.earlyRecomputationBeta, cache.random) {
+		return nil, errKeyExpiredEarly
+	}
+
+	return cache.access(key, value, revalidator)
+}
+
+// it's used for a live value; it doesn't affect statistics
+func (cache Cache) access(
+	key hashmap.Key,
+	value models.Value,
+	revalidator Loader,
+) (data interface{}, err error) {
+	value.Touch(cache.clock)
+	if cache.evictionPolicy != nil {
+		cache.evictionPolicy.OnAccess(key)
+	}
+
+	isStale := value.IsStale(cache.clock)
+	if isStale {
+		cache.startRevalidation(key, value, revalidator)
+	}
+
+	if value.Err != nil {
+		return nil, value.Err
+	}
+	if isStale {
+		return value.Data, ErrKeyStale
+	}
+
+	return value.Data, nil
+}
+
+// it takes into account expiration on idleness
+func effectiveExpirationTime(value models.Value) time.Time {
+	expirationTime := value.ExpirationTime
+	idleExpirationTime := value.IdleExpirationTime()
+	if !idleExpirationTime.IsZero() &&
+		(expirationTime.IsZero() || idleExpirationTime.Before(expirationTime)) {
+		expirationTime = idleExpirationTime
+	}
+
+	return expirationTime
+}
+
+func (cache Cache) newValue(
+	key hashmap.Key,
+	data interface{},
+	ttl time.Duration,
+	options []ValueOption,
+) models.Value {
+	var config valueConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	if !config.isCostSet && cache.weigher != nil {
+		config.cost = cache.weigher(key, data)
+	}
+
+	currentTime := cache.clock()
+	expirationTime := cache.expirationTime(currentTime, ttl)
+
+	if !config.isStaleTTLSet {
+		config.staleTTL = cache.staleTTL
 	}
 
-	cache.storage.Set(key, models.Value{
+	var staleTime time.Time
+	var revalidation *atomic.Bool
+	if config.staleTTL > 0 && !expirationTime.IsZero() {
+		staleTime = expirationTime
+		expirationTime = expirationTime.Add(config.staleTTL)
+		revalidation = new(atomic.Bool)
+	}
+
+	var accessTime *models.AccessTime
+	if config.idleTTL != 0 {
+		accessTime = models.NewAccessTime(currentTime)
+	}
+
+	return models.Value{
 		Data:           data,
 		ExpirationTime: expirationTime,
+		Cost:           config.cost,
+		IdleTimeout:    config.idleTTL,
+		AccessTime:     accessTime,
+		Tags:           config.tags,
+		StaleTime:      staleTime,
+		Revalidation:   revalidation,
+		RecomputeCost:  config.recomputeCost,
+	}
+}
+
+func (cache Cache) setValue(key hashmap.Key, value models.Value) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		transaction.set(value)
 	})
 }
 
-// Delete ...
-func (cache Cache) Delete(key hashmap.Key) {
-	cache.storage.Delete(key)
+func (cache Cache) isCleared(value models.Value) bool {
+	return value.Generation != cache.generation.Load()
+}
+
+func (cache Cache) isBounded() bool {
+	return cache.maxSize > 0 || cache.maxCost > 0
+}
+
+func (cache Cache) isOverflowed(additionalSize int64, additionalCost int64) bool {
+	if cache.maxSize > 0 &&
+		cache.size.Load()+additionalSize > int64(cache.maxSize) {
+		return true
+	}
+
+	return cache.maxCost > 0 &&
+		cache.cost.Load()+additionalCost > cache.maxCost
+}
+
+func (cache Cache) admit(key hashmap.Key, cost int64) bool {
+	if !cache.isOverflowed(1, cost) {
+		return true
+	}
+
+	admitter, ok := cache.evictionPolicy.(eviction.Admitter)
+	return !ok || admitter.Admit(key)
+}
+
+func (cache Cache) deleteExpired(key hashmap.Key) {
+	// garbage collection iterates over the whole storage, so it meets keys
+	// of namespaces too
+	if namespacedKey, ok := key.(namespacedKey); ok {
+		if namespace, ok := cache.namespaces.get(namespacedKey.namespace.name); ok {
+			namespace.deleteExpired(namespacedKey.key)
+			return
+		}
+	}
+
+	cache.deleteIf(key, RemovalReasonExpired, func(value models.Value) bool {
+		return value.IsExpired(cache.clock)
+	})
+}
+
+func (cache Cache) deleteIf(
+	key hashmap.Key,
+	reason RemovalReason,
+	condition func(value models.Value) bool,
+) (ok bool) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		if transaction.isPresent &&
+			!condition(transaction.data.(models.Value)) {
+			return
+		}
+
+		ok = transaction.delete(reason)
+	})
+
+	return ok
+}
+
+// the maximal size and cost can be exceeded temporarily by concurrent setting
+func (cache Cache) evict() {
+	for cache.isOverflowed(0, 0) {
+		victim, ok := cache.evictionPolicy.Victim()
+		if !ok {
+			return
+		}
+
+		cache.deleteIf(victim, RemovalReasonEvicted, func(value models.Value) bool {
+			return true
+		})
+	}
 }
 
 func (cache Cache) iterateWithExpiredHandler(
@@ -134,11 +619,17 @@ func (cache Cache) iterateWithExpiredHandler(
 	return cache.storage.Iterate(
 		hashmap.WithInterruption(ctx, func(key hashmap.Key, data interface{}) bool {
 			value := data.(models.Value)
+			if isNamespacedKey(key) || cache.isCleared(value) {
+				return true
+			}
 			if value.IsExpired(cache.clock) {
 				expiredHandler(key)
 
 				return true
 			}
+			if value.Err != nil {
+				return true
+			}
 
 			return handler(key, value.Data)
 		}),
@@ -22,7 +22,8 @@ func (key IntKey) Hash() int {
 }
 
 func (key IntKey) Equals(other hashmap.Key) bool {
-	return key == other.(IntKey)
+	otherKey, ok := other.(IntKey)
+	return ok && key == otherKey
 }
 
 func BenchmarkCacheGetting(benchmark *testing.B) {
@@ -97,3 +98,81 @@ func setItem(cache Cache, key int) {
 
 	cache.Set(IntKey(key), key, ttl)
 }
+
+func BenchmarkCacheBatchOperations(benchmark *testing.B) {
+	const storageSize = 1e4
+
+	for _, data := range []struct {
+		name      string
+		benchmark func(cache Cache, keys []hashmap.Key)
+	}{
+		{
+			name: "Get",
+			benchmark: func(cache Cache, keys []hashmap.Key) {
+				for _, key := range keys {
+					cache.Get(key) // nolint: errcheck
+				}
+			},
+		},
+		{
+			name: "GetMany",
+			benchmark: func(cache Cache, keys []hashmap.Key) {
+				cache.GetMany(keys)
+			},
+		},
+		{
+			name: "Set",
+			benchmark: func(cache Cache, keys []hashmap.Key) {
+				for _, key := range keys {
+					cache.Set(key, "data", time.Minute)
+				}
+			},
+		},
+		{
+			name: "SetMany",
+			benchmark: func(cache Cache, keys []hashmap.Key) {
+				entries := make([]Entry, len(keys))
+				for index, key := range keys {
+					entries[index] = Entry{Key: key, Data: "data"}
+				}
+
+				cache.SetMany(entries, time.Minute)
+			},
+		},
+		{
+			name: "Delete",
+			benchmark: func(cache Cache, keys []hashmap.Key) {
+				for _, key := range keys {
+					cache.Delete(key)
+				}
+			},
+		},
+		{
+			name: "DeleteMany",
+			benchmark: func(cache Cache, keys []hashmap.Key) {
+				cache.DeleteMany(keys)
+			},
+		},
+	} {
+		for _, batchSize := range []int{10, 100, 1000} {
+			name := fmt.Sprintf("%s/%d", data.name, batchSize)
+			benchmark.Run(name, func(benchmark *testing.B) {
+				cache := NewCache()
+				for i := 0; i < storageSize; i++ {
+					setItem(cache, i)
+				}
+
+				keys := make([]hashmap.Key, batchSize)
+				for index := range keys {
+					keys[index] = IntKey(rand.Intn(storageSize))
+				}
+
+				benchmark.ResetTimer()
+
+				for i := 0; i < benchmark.N; i++ {
+					data.benchmark(cache, keys)
+				}
+			})
+		}
+	}
+}
@@ -2,13 +2,21 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-cache/eviction"
+	"github.com/thewizardplusplus/go-cache/gc"
 	"github.com/thewizardplusplus/go-cache/models"
 	hashmap "github.com/thewizardplusplus/go-hashmap"
 )
@@ -33,10 +41,22 @@ func TestNewCache(test *testing.T) {
 	}
 
 	for _, data := range []struct {
-		name          string
-		args          args
-		wantStorage   hashmap.Storage
-		wantClockTime time.Time
+		name               string
+		args               args
+		wantStorage        hashmap.Storage
+		wantClockTime      time.Time
+		wantMaxSize        int
+		wantWeigher        assert.ValueAssertionFunc
+		wantMaxCost        int64
+		wantEvictionPolicy eviction.Policy
+		wantTTLJitter      ttlJitter
+
+		wantRemovalListener        assert.ValueAssertionFunc
+		wantIsRemovalListenerSync  bool
+		wantEarlyRecomputationBeta float64
+		wantStaleTTL               time.Duration
+		wantRevalidator            assert.ValueAssertionFunc
+		wantAOF                    *AOF
 	}{
 		{
 			name: "with default options",
@@ -70,6 +90,133 @@ func TestNewCache(test *testing.T) {
 			wantStorage:   new(MockStorage),
 			wantClockTime: clock(),
 		},
+		{
+			name: "with the set maximal size",
+			args: args{
+				options: []Option{WithMaxSize(23)},
+			},
+			wantStorage:        hashmap.NewConcurrentHashMap(),
+			wantClockTime:      time.Now(),
+			wantMaxSize:        23,
+			wantEvictionPolicy: eviction.NewLRU(),
+		},
+		{
+			name: "with the set maximal size and eviction policy",
+			args: args{
+				options: []Option{
+					WithMaxSize(23),
+					WithEvictionPolicy(new(MockEvictionPolicy)),
+				},
+			},
+			wantStorage:        hashmap.NewConcurrentHashMap(),
+			wantClockTime:      time.Now(),
+			wantMaxSize:        23,
+			wantEvictionPolicy: new(MockEvictionPolicy),
+		},
+		{
+			name: "with the set weigher",
+			args: args{
+				options: []Option{
+					WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+						return 23
+					}),
+				},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantWeigher:   assert.NotNil,
+		},
+		{
+			name: "with the set maximal cost",
+			args: args{
+				options: []Option{WithMaxCost(23)},
+			},
+			wantStorage:        hashmap.NewConcurrentHashMap(),
+			wantClockTime:      time.Now(),
+			wantMaxCost:        23,
+			wantEvictionPolicy: eviction.NewLRU(),
+		},
+		{
+			name: "with the set removal listener",
+			args: args{
+				options: []Option{
+					WithRemovalListener(
+						func(key hashmap.Key, data interface{}, reason RemovalReason) {},
+					),
+				},
+			},
+			wantStorage:               hashmap.NewConcurrentHashMap(),
+			wantClockTime:             time.Now(),
+			wantRemovalListener:       assert.NotNil,
+			wantIsRemovalListenerSync: false,
+		},
+		{
+			name: "with the set sync removal listener",
+			args: args{
+				options: []Option{
+					WithSyncRemovalListener(
+						func(key hashmap.Key, data interface{}, reason RemovalReason) {},
+					),
+				},
+			},
+			wantStorage:               hashmap.NewConcurrentHashMap(),
+			wantClockTime:             time.Now(),
+			wantRemovalListener:       assert.NotNil,
+			wantIsRemovalListenerSync: true,
+		},
+		{
+			name: "with the set TTL jitter",
+			args: args{
+				options: []Option{
+					WithTTLJitter(0.1),
+					WithTTLJitterRange(-time.Second, 2*time.Second),
+				},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantTTLJitter: ttlJitter{
+				percent:   0.1,
+				minJitter: -time.Second,
+				maxJitter: 2 * time.Second,
+			},
+		},
+		{
+			name: "with the set early recomputation",
+			args: args{
+				options: []Option{WithEarlyRecomputation(1.5)},
+			},
+			wantStorage:                hashmap.NewConcurrentHashMap(),
+			wantClockTime:              time.Now(),
+			wantEarlyRecomputationBeta: 1.5,
+		},
+		{
+			name: "with the set stale serving",
+			args: args{
+				options: []Option{
+					WithStaleTTL(time.Minute),
+					WithRevalidator(func(ctx context.Context, key hashmap.Key) (
+						data interface{},
+						ttl time.Duration,
+						err error,
+					) {
+						return "data", 0, nil
+					}),
+				},
+			},
+			wantStorage:     hashmap.NewConcurrentHashMap(),
+			wantClockTime:   time.Now(),
+			wantStaleTTL:    time.Minute,
+			wantRevalidator: assert.NotNil,
+		},
+		{
+			name: "with the set AOF",
+			args: args{
+				options: []Option{WithAOF(&AOF{path: "cache.aof"})},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantAOF:       &AOF{path: "cache.aof"},
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			got := NewCache(data.args.options...)
@@ -81,12 +228,55 @@ func TestNewCache(test *testing.T) {
 				mock.AssertExpectationsForObjects(test, got.storage)
 			}
 			assert.Equal(test, data.wantStorage, got.storage)
+			assert.Equal(test, data.wantMaxSize, got.maxSize)
+			assert.Equal(test, data.wantMaxCost, got.maxCost)
+			assert.Equal(test, data.wantEvictionPolicy, got.evictionPolicy)
+			assert.Equal(test, data.wantTTLJitter, got.ttlJitter)
 
 			// don't use the reflect.Value.Pointer() method for this check; see details:
 			// * https://golang.org/pkg/reflect/#Value.Pointer
 			// * https://stackoverflow.com/a/9644797
 			require.NotNil(test, got.clock)
 			assert.WithinDuration(test, data.wantClockTime, got.clock(), time.Hour)
+			assert.NotNil(test, got.random)
+
+			if data.wantWeigher != nil {
+				data.wantWeigher(test, got.weigher)
+			} else {
+				assert.Nil(test, got.weigher)
+			}
+
+			if data.wantRemovalListener != nil {
+				data.wantRemovalListener(test, got.removalListener)
+			} else {
+				assert.Nil(test, got.removalListener)
+			}
+			assert.Equal(
+				test,
+				data.wantIsRemovalListenerSync,
+				got.isRemovalListenerSync,
+			)
+			assert.Equal(
+				test,
+				data.wantEarlyRecomputationBeta,
+				got.earlyRecomputationBeta,
+			)
+			assert.Equal(test, data.wantStaleTTL, got.staleTTL)
+
+			if data.wantRevalidator != nil {
+				data.wantRevalidator(test, got.revalidator)
+			} else {
+				assert.Nil(test, got.revalidator)
+			}
+			assert.Equal(test, data.wantAOF, got.aof)
+
+			assert.NotNil(test, got.loads)
+			assert.NotNil(test, got.locks)
+			assert.NotNil(test, got.events)
+			assert.NotNil(test, got.stats)
+			assert.NotNil(test, got.size)
+			assert.NotNil(test, got.cost)
+			assert.NotNil(test, got.generation)
 		})
 	}
 }
@@ -100,7 +290,11 @@ func TestNewCacheWithGC(test *testing.T) {
 
 	gcFactoryHandler := new(MockGCFactoryHandler)
 	gcFactoryHandler.
-		On("NewGC", storage, mock.AnythingOfType("models.Clock")).
+		On(
+			"NewGC",
+			mock.AnythingOfType("cache.gcStorage"),
+			mock.AnythingOfType("models.Clock"),
+		).
 		Return(gcInstance)
 
 	const gcPeriod = 100 * time.Millisecond
@@ -123,6 +317,44 @@ func TestNewCacheWithGC(test *testing.T) {
 	// * https://stackoverflow.com/a/9644797
 	require.NotNil(test, cache.clock)
 	assert.WithinDuration(test, clock(), cache.clock(), time.Hour)
+
+	assert.NotNil(test, cache.loads)
+}
+
+func TestNewCacheWithGC_withGCReportHandler(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reports := make(chan gc.Report, 1)
+	const gcPeriod = 10 * time.Millisecond
+	cache := NewCacheWithGC(
+		ctx,
+		WithGCAndGCFactory(func(storage hashmap.Storage, clock models.Clock) gc.GC {
+			return gc.NewTotalGC(storage, gc.TotalGCWithClock(clock))
+		}),
+		WithGCAndGCPeriod(gcPeriod),
+		WithGCAndGCReportHandler(func(report gc.Report) {
+			select {
+			case reports <- report:
+			default:
+			}
+		}),
+	)
+	// the expired value is set last, so a report that sees it
+	// sees both values
+	cache.Set(IntKey(42), "two", 0)
+	cache.Set(IntKey(23), "one", -time.Second)
+
+	var gotReport gc.Report
+	require.Eventually(test, func() bool {
+		gotReport = <-reports
+		return gotReport.ExpiredCount != 0
+	}, time.Second, gcPeriod)
+
+	assert.Equal(test, 2, gotReport.IteratedCount)
+	assert.Equal(test, 1, gotReport.ExpiredCount)
+	assert.Equal(test, 1, gotReport.Rounds)
+	assert.Equal(test, gc.StopReasonCompleted, gotReport.StopReason)
 }
 
 func TestCache_Get(test *testing.T) {
@@ -204,7 +436,7 @@ func TestCache_Get(test *testing.T) {
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			cache := Cache{data.fields.storage, data.fields.clock}
+			cache := NewCache(WithStorage(data.fields.storage), WithClock(data.fields.clock))
 			gotData, gotErr := cache.Get(data.args.key)
 
 			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
@@ -236,7 +468,7 @@ func TestCache_GetWithGC(test *testing.T) {
 				storage: func() hashmap.Storage {
 					storage := new(MockStorage)
 					storage.
-						On("Get", NewMockKeyWithID(23)).
+						On("Get", IntKey(23)).
 						Return(
 							models.Value{Data: "data", ExpirationTime: clock().Add(time.Second)},
 							true,
@@ -247,7 +479,7 @@ func TestCache_GetWithGC(test *testing.T) {
 				clock: clock,
 			},
 			args: args{
-				key: NewMockKeyWithID(23),
+				key: IntKey(23),
 			},
 			wantData: "data",
 			wantErr:  assert.NoError,
@@ -257,14 +489,14 @@ func TestCache_GetWithGC(test *testing.T) {
 			fields: fields{
 				storage: func() hashmap.Storage {
 					storage := new(MockStorage)
-					storage.On("Get", NewMockKeyWithID(23)).Return(nil, false)
+					storage.On("Get", IntKey(23)).Return(nil, false)
 
 					return storage
 				}(),
 				clock: clock,
 			},
 			args: args{
-				key: NewMockKeyWithID(23),
+				key: IntKey(23),
 			},
 			wantData: nil,
 			wantErr:  assert.Error,
@@ -275,35 +507,535 @@ func TestCache_GetWithGC(test *testing.T) {
 				storage: func() hashmap.Storage {
 					storage := new(MockStorage)
 					storage.
-						On("Get", NewMockKeyWithID(23)).
+						On("Get", IntKey(23)).
 						Return(
 							models.Value{Data: "data", ExpirationTime: clock().Add(-time.Second)},
 							true,
-						)
-					storage.On("Delete", NewMockKeyWithID(23))
+						).
+						Twice()
+					storage.On("Delete", IntKey(23))
 
 					return storage
 				}(),
 				clock: clock,
 			},
 			args: args{
-				key: NewMockKeyWithID(23),
+				key: IntKey(23),
 			},
 			wantData: nil,
 			wantErr:  assert.Error,
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			cache := Cache{data.fields.storage, data.fields.clock}
+			cache :=
+				NewCache(WithStorage(data.fields.storage), WithClock(data.fields.clock))
 			gotData, gotErr := cache.GetWithGC(data.args.key)
 
-			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
+			mock.AssertExpectationsForObjects(test, data.fields.storage)
+			assert.Equal(test, data.wantData, gotData)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestCache_GetIncludingExpired(test *testing.T) {
+	for _, data := range []struct {
+		name               string
+		prepare            func(cache Cache)
+		wantData           interface{}
+		wantExpirationTime time.Time
+		wantErr            error
+		wantStats          Stats
+	}{
+		{
+			name:               "with a missed value",
+			prepare:            func(cache Cache) {},
+			wantData:           nil,
+			wantExpirationTime: time.Time{},
+			wantErr:            ErrKeyMissed,
+			wantStats:          Stats{Misses: 1},
+		},
+		{
+			name: "with a persistent value",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			wantData:           "data",
+			wantExpirationTime: time.Time{},
+			wantErr:            nil,
+			wantStats:          Stats{Hits: 1, Sets: 1},
+		},
+		{
+			name: "with a live value",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Minute)
+			},
+			wantData:           "data",
+			wantExpirationTime: clock().Add(time.Minute),
+			wantErr:            nil,
+			wantStats:          Stats{Hits: 1, Sets: 1},
+		},
+		{
+			name: "with an expired value",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", -time.Minute)
+			},
+			wantData:           "data",
+			wantExpirationTime: clock().Add(-time.Minute),
+			wantErr:            ErrKeyExpired,
+			wantStats:          Stats{ExpiredMisses: 1, Sets: 1},
+		},
+		{
+			name: "with a value expired on idleness",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithIdleTTL(-time.Second),
+				)
+			},
+			wantData:           "data",
+			wantExpirationTime: clock().Add(-time.Second),
+			wantErr:            ErrKeyExpired,
+			wantStats:          Stats{ExpiredMisses: 1, Sets: 1},
+		},
+		{
+			name: "with a value accessed before",
+			prepare: func(cache Cache) {
+				cache.storage.Set(IntKey(23), models.Value{
+					Data:        "data",
+					IdleTimeout: time.Minute,
+					AccessTime:  models.NewAccessTime(clock().Add(-30 * time.Second)),
+				})
+			},
+			// the expiration on idleness is postponed by the access
+			wantData:           "data",
+			wantExpirationTime: clock().Add(time.Minute),
+			wantErr:            nil,
+			wantStats:          Stats{Hits: 1},
+		},
+		{
+			name: "with a stale value",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					-time.Minute,
+					ValueWithStaleTTL(2*time.Minute),
+				)
+			},
+			wantData:           "data",
+			wantExpirationTime: clock().Add(time.Minute),
+			wantErr:            ErrKeyStale,
+			wantStats:          Stats{StaleHits: 1, Sets: 1},
+		},
+		{
+			name: "with a negative value",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), time.Minute)
+			},
+			wantData:           nil,
+			wantExpirationTime: clock().Add(time.Minute),
+			wantErr:            ErrKeyNegative,
+			wantStats:          Stats{NegativeHits: 1, Sets: 1},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			gotData, gotExpirationTime, gotErr := cache.GetIncludingExpired(IntKey(23))
+
+			assert.Equal(test, data.wantData, gotData)
+			assert.Equal(test, data.wantExpirationTime, gotExpirationTime)
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(test, data.wantStats, cache.Stats())
+			assert.Equal(test, int(data.wantStats.Sets), cache.Len())
+		})
+	}
+}
+
+func TestCache_GetOrLoad(test *testing.T) {
+	type fields struct {
+		storage hashmap.Storage
+		clock   models.Clock
+	}
+	type args struct {
+		ctx    context.Context
+		key    hashmap.Key
+		loader LoaderHandler
+	}
+
+	for _, data := range []struct {
+		name     string
+		fields   fields
+		args     args
+		wantData interface{}
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with a present key",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.
+						On("Get", IntKey(23)).
+						Return(
+							models.Value{Data: "data", ExpirationTime: clock().Add(time.Second)},
+							true,
+						)
+
+					return storage
+				}(),
+				clock: clock,
+			},
+			args: args{
+				ctx:    context.Background(),
+				key:    IntKey(23),
+				loader: new(MockLoaderHandler),
+			},
+			wantData: "data",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with a missed key",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false).Times(3)
+					storage.On("Set", IntKey(23), models.Value{
+						Data:           "data",
+						ExpirationTime: clock().Add(time.Second),
+					})
+
+					return storage
+				}(),
+				clock: clock,
+			},
+			args: args{
+				ctx: context.Background(),
+				key: IntKey(23),
+				loader: func() LoaderHandler {
+					loader := new(MockLoaderHandler)
+					loader.
+						On("Load", mock.Anything, IntKey(23)).
+						Return("data", time.Second, nil)
+
+					return loader
+				}(),
+			},
+			wantData: "data",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with an expired key",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.
+						On("Get", IntKey(23)).
+						Return(
+							models.Value{Data: "data", ExpirationTime: clock().Add(-time.Second)},
+							true,
+						).
+						Times(3)
+					storage.On("Set", IntKey(23), models.Value{
+						Data:           "new data",
+						ExpirationTime: time.Time{},
+					})
+
+					return storage
+				}(),
+				clock: clock,
+			},
+			args: args{
+				ctx: context.Background(),
+				key: IntKey(23),
+				loader: func() LoaderHandler {
+					loader := new(MockLoaderHandler)
+					loader.
+						On("Load", mock.Anything, IntKey(23)).
+						Return("new data", time.Duration(0), nil)
+
+					return loader
+				}(),
+			},
+			wantData: "new data",
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error from the loader",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false).Twice()
+
+					return storage
+				}(),
+				clock: clock,
+			},
+			args: args{
+				ctx: context.Background(),
+				key: IntKey(23),
+				loader: func() LoaderHandler {
+					loader := new(MockLoaderHandler)
+					loader.
+						On("Load", mock.Anything, IntKey(23)).
+						Return(nil, time.Duration(0), iotest.ErrTimeout)
+
+					return loader
+				}(),
+			},
+			wantData: nil,
+			wantErr:  assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache :=
+				NewCache(WithStorage(data.fields.storage), WithClock(data.fields.clock))
+			gotData, gotErr :=
+				cache.GetOrLoad(data.args.ctx, data.args.key, data.args.loader.Load)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.loader)
 			assert.Equal(test, data.wantData, gotData)
 			data.wantErr(test, gotErr)
 		})
 	}
 }
 
+func TestCache_GetOrLoad_withConcurrentCalls(test *testing.T) {
+	const callCount = 10
+
+	var loadCount int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key hashmap.Key) (
+		data interface{},
+		ttl time.Duration,
+		err error,
+	) {
+		atomic.AddInt32(&loadCount, 1)
+		<-release
+
+		return "data", 0, nil
+	}
+
+	cache := NewCache(WithClock(clock))
+	key := IntKey(23)
+
+	var waitGroup sync.WaitGroup
+	gotResults := make(chan interface{}, callCount)
+	for i := 0; i < callCount; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			data, err := cache.GetOrLoad(context.Background(), key, loader)
+			assert.NoError(test, err)
+
+			gotResults <- data
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	waitGroup.Wait()
+	close(gotResults)
+
+	for data := range gotResults {
+		assert.Equal(test, "data", data)
+	}
+	assert.Equal(test, int32(1), atomic.LoadInt32(&loadCount))
+
+	gotData, gotErr := cache.Get(key)
+	assert.Equal(test, "data", gotData)
+	assert.NoError(test, gotErr)
+}
+
+func TestCache_GetOrLoad_withCanceledWaiter(test *testing.T) {
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key hashmap.Key) (
+		data interface{},
+		ttl time.Duration,
+		err error,
+	) {
+		select {
+		case <-release:
+			return "data", 0, nil
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+	}
+
+	cache := NewCache(WithClock(clock))
+	key := IntKey(23)
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	canceledResult := make(chan error)
+	go func() {
+		_, err := cache.GetOrLoad(canceledCtx, key, loader)
+		canceledResult <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	otherResult := make(chan interface{})
+	go func() {
+		data, err := cache.GetOrLoad(context.Background(), key, loader)
+		assert.NoError(test, err)
+
+		otherResult <- data
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assert.Equal(test, context.Canceled, <-canceledResult)
+
+	close(release)
+	assert.Equal(test, "data", <-otherResult)
+}
+
+func TestCache_GetOrLoad_withPanickingLoader(test *testing.T) {
+	loader := func(ctx context.Context, key hashmap.Key) (
+		data interface{},
+		ttl time.Duration,
+		err error,
+	) {
+		panic("test")
+	}
+
+	cache := NewCache(WithClock(clock))
+	key := IntKey(23)
+
+	gotData, gotErr := cache.GetOrLoad(context.Background(), key, loader)
+	assert.Nil(test, gotData)
+	assert.True(test, errors.Is(gotErr, ErrLoaderPanicked))
+
+	_, gotErr = cache.Get(key)
+	assert.Equal(test, ErrKeyMissed, gotErr)
+}
+
+func TestCache_Get_withEarlyRecomputation(test *testing.T) {
+	currentTime := clock()
+	cache := NewCache(
+		WithClock(func() time.Time { return currentTime }),
+		WithRandom(rand.New(rand.NewSource(23)).Float64),
+		WithEarlyRecomputation(1),
+	)
+	cache.SetWithOptions(
+		IntKey(23),
+		"data",
+		time.Minute,
+		ValueWithRecomputeCost(10*time.Second),
+	)
+
+	const getCount = 1000
+	var expiredCounts []int
+	for _, remainingTTL := range []time.Duration{
+		50 * time.Second,
+		20 * time.Second,
+		5 * time.Second,
+		100 * time.Millisecond,
+	} {
+		currentTime = clock().Add(time.Minute - remainingTTL)
+
+		var expiredCount int
+		for i := 0; i < getCount; i++ {
+			if _, err := cache.Get(IntKey(23)); err == ErrKeyExpired {
+				expiredCount++
+			}
+		}
+
+		expiredCounts = append(expiredCounts, expiredCount)
+	}
+
+	// the probability of the early expiration rises as the expiration time
+	// gets closer
+	assert.Less(test, expiredCounts[0], getCount/20)
+	assert.Less(test, expiredCounts[0], expiredCounts[1])
+	assert.Less(test, expiredCounts[1], expiredCounts[2])
+	assert.Less(test, expiredCounts[2], expiredCounts[3])
+	assert.Greater(test, expiredCounts[3], getCount*19/20)
+
+	var expiredCountSum int
+	for _, expiredCount := range expiredCounts {
+		expiredCountSum += expiredCount
+	}
+	assert.Equal(test, 1, cache.Len())
+	assert.Equal(test, int64(expiredCountSum), cache.Stats().ExpiredMisses)
+}
+
+func TestCache_GetOrLoad_withEarlyRecomputation(test *testing.T) {
+	for _, data := range []struct {
+		name                string
+		options             []Option
+		remainingTTL        time.Duration
+		wantData            interface{}
+		wantLoaderCallCount int
+	}{
+		{
+			name:                "without early recomputation",
+			options:             nil,
+			remainingTTL:        time.Second,
+			wantData:            "data #1",
+			wantLoaderCallCount: 1,
+		},
+		{
+			name:                "with a remaining time to live greater than a gap",
+			options:             []Option{WithEarlyRecomputation(1)},
+			remainingTTL:        3 * time.Second,
+			wantData:            "data #1",
+			wantLoaderCallCount: 1,
+		},
+		{
+			name:                "with a remaining time to live less than a gap",
+			options:             []Option{WithEarlyRecomputation(1)},
+			remainingTTL:        time.Second,
+			wantData:            "data #2",
+			wantLoaderCallCount: 2,
+		},
+		{
+			name:                "with a gap scaled by beta",
+			options:             []Option{WithEarlyRecomputation(2)},
+			remainingTTL:        3 * time.Second,
+			wantData:            "data #2",
+			wantLoaderCallCount: 2,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			currentTime := clock()
+			options := []Option{
+				WithClock(func() time.Time { return currentTime }),
+				// with it, the gap before the expiration equals the weighted
+				// recomputation cost
+				WithRandom(func() float64 { return 1 - math.Exp(-1) }),
+			}
+			cache := NewCache(append(options, data.options...)...)
+
+			var loaderCallCount int
+			loader := func(ctx context.Context, key hashmap.Key) (
+				data interface{},
+				ttl time.Duration,
+				err error,
+			) {
+				loaderCallCount++
+				currentTime = currentTime.Add(2 * time.Second)
+
+				return fmt.Sprintf("data #%d", loaderCallCount), time.Minute, nil
+			}
+			cache.GetOrLoad(context.Background(), IntKey(23), loader) // nolint: errcheck
+
+			currentTime = currentTime.Add(time.Minute - data.remainingTTL)
+			gotData, gotErr :=
+				cache.GetOrLoad(context.Background(), IntKey(23), loader)
+
+			assert.Equal(test, data.wantData, gotData)
+			assert.NoError(test, gotErr)
+			assert.Equal(test, data.wantLoaderCallCount, loaderCallCount)
+		})
+	}
+}
+
 func TestCache_Iterate(test *testing.T) {
 	type bucket struct {
 		key   hashmap.Key
@@ -470,10 +1202,7 @@ func TestCache_Iterate(test *testing.T) {
 			// reset the random generator to make tests deterministic
 			rand.Seed(1)
 
-			cache := Cache{
-				storage: hashmap.NewConcurrentHashMap(),
-				clock:   data.fields.clock,
-			}
+			cache := NewCache(WithClock(data.fields.clock))
 			for _, bucket := range data.fields.buckets {
 				cache.Set(bucket.key, bucket.value, bucket.ttl)
 			}
@@ -673,10 +1402,7 @@ func TestCache_IterateWithGC(test *testing.T) {
 			// reset the random generator to make tests deterministic
 			rand.Seed(1)
 
-			cache := Cache{
-				storage: hashmap.NewConcurrentHashMap(),
-				clock:   data.fields.clock,
-			}
+			cache := NewCache(WithClock(data.fields.clock))
 			for _, bucket := range data.fields.buckets {
 				cache.Set(bucket.key, bucket.value, bucket.ttl)
 			}
@@ -727,7 +1453,8 @@ func TestCache_Set(test *testing.T) {
 			fields: fields{
 				storage: func() hashmap.Storage {
 					storage := new(MockStorage)
-					storage.On("Set", NewMockKeyWithID(23), models.Value{
+					storage.On("Get", IntKey(23)).Return(nil, false)
+					storage.On("Set", IntKey(23), models.Value{
 						Data:           "data",
 						ExpirationTime: time.Time{},
 					})
@@ -737,7 +1464,7 @@ func TestCache_Set(test *testing.T) {
 				clock: clock,
 			},
 			args: args{
-				key:  NewMockKeyWithID(23),
+				key:  IntKey(23),
 				data: "data",
 				ttl:  0,
 			},
@@ -747,8 +1474,9 @@ func TestCache_Set(test *testing.T) {
 			fields: fields{
 				storage: func() hashmap.Storage {
 					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false)
 					storage.
-						On("Set", NewMockKeyWithID(23), models.Value{
+						On("Set", IntKey(23), models.Value{
 							Data:           "data",
 							ExpirationTime: clock().Add(time.Second),
 						})
@@ -758,31 +1486,591 @@ func TestCache_Set(test *testing.T) {
 				clock: clock,
 			},
 			args: args{
-				key:  NewMockKeyWithID(23),
+				key:  IntKey(23),
 				data: "data",
 				ttl:  time.Second,
 			},
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			cache := Cache{data.fields.storage, data.fields.clock}
+			cache :=
+				NewCache(WithStorage(data.fields.storage), WithClock(data.fields.clock))
 			cache.Set(data.args.key, data.args.data, data.args.ttl)
 
-			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
+			mock.AssertExpectationsForObjects(test, data.fields.storage)
 		})
 	}
 }
 
+func TestCache_SetWithOptions(test *testing.T) {
+	type fields struct {
+		storage hashmap.Storage
+		clock   models.Clock
+		weigher Weigher
+	}
+	type args struct {
+		key     hashmap.Key
+		data    interface{}
+		ttl     time.Duration
+		options []ValueOption
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		args   args
+	}{
+		{
+			name: "without options",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false)
+					storage.On("Set", IntKey(23), models.Value{
+						Data:           "data",
+						ExpirationTime: clock().Add(time.Second),
+					})
+
+					return storage
+				}(),
+				clock:   clock,
+				weigher: nil,
+			},
+			args: args{
+				key:     IntKey(23),
+				data:    "data",
+				ttl:     time.Second,
+				options: nil,
+			},
+		},
+		{
+			name: "with the weigher",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false)
+					storage.On("Set", IntKey(23), models.Value{
+						Data:           "data",
+						ExpirationTime: clock().Add(time.Second),
+						Cost:           4,
+					})
+
+					return storage
+				}(),
+				clock: clock,
+				weigher: func(key hashmap.Key, data interface{}) int64 {
+					return int64(len(data.(string)))
+				},
+			},
+			args: args{
+				key:     IntKey(23),
+				data:    "data",
+				ttl:     time.Second,
+				options: nil,
+			},
+		},
+		{
+			name: "with the weigher and the set cost",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false)
+					storage.On("Set", IntKey(23), models.Value{
+						Data:           "data",
+						ExpirationTime: clock().Add(time.Second),
+						Cost:           42,
+					})
+
+					return storage
+				}(),
+				clock: clock,
+				weigher: func(key hashmap.Key, data interface{}) int64 {
+					return int64(len(data.(string)))
+				},
+			},
+			args: args{
+				key:     IntKey(23),
+				data:    "data",
+				ttl:     time.Second,
+				options: []ValueOption{ValueWithCost(42)},
+			},
+		},
+		{
+			name: "with the set idle TTL",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Get", IntKey(23)).Return(nil, false)
+					storage.On("Set", IntKey(23), models.Value{
+						Data:           "data",
+						ExpirationTime: time.Time{},
+						IdleTimeout:    time.Second,
+						AccessTime:     models.NewAccessTime(clock()),
+					})
+
+					return storage
+				}(),
+				clock:   clock,
+				weigher: nil,
+			},
+			args: args{
+				key:     IntKey(23),
+				data:    "data",
+				ttl:     0,
+				options: []ValueOption{ValueWithIdleTTL(time.Second)},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(
+				WithStorage(data.fields.storage),
+				WithClock(data.fields.clock),
+				WithWeigher(data.fields.weigher),
+			)
+			cache.SetWithOptions(
+				data.args.key,
+				data.args.data,
+				data.args.ttl,
+				data.args.options...,
+			)
+
+			mock.AssertExpectationsForObjects(test, data.fields.storage)
+		})
+	}
+}
+
+func TestCache_Get_withIdleTTL(test *testing.T) {
+	currentTime := clock()
+	cache := NewCache(WithClock(func() time.Time { return currentTime }))
+	cache.SetWithOptions(
+		IntKey(23),
+		"data",
+		3*time.Second,
+		ValueWithIdleTTL(time.Second),
+	)
+
+	// each getting postpones the expiration on idleness
+	for i := 0; i < 2; i++ {
+		currentTime = currentTime.Add(time.Second)
+
+		gotData, gotErr := cache.Get(IntKey(23))
+		assert.Equal(test, "data", gotData)
+		assert.NoError(test, gotErr)
+	}
+
+	// the expiration on idleness can't be postponed beyond the usual one
+	currentTime = currentTime.Add(time.Second + time.Nanosecond)
+
+	_, gotErr := cache.Get(IntKey(23))
+	assert.Equal(test, ErrKeyExpired, gotErr)
+
+	// without access, the value expires on idleness
+	cache.SetWithOptions(IntKey(23), "data", 0, ValueWithIdleTTL(time.Second))
+	currentTime = currentTime.Add(time.Second + time.Nanosecond)
+
+	_, gotErr = cache.Get(IntKey(23))
+	assert.Equal(test, ErrKeyExpired, gotErr)
+}
+
+func TestCache_Set_withWeigher(test *testing.T) {
+	cache := NewCache(
+		WithClock(clock),
+		WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+			return int64(len(data.(string)))
+		}),
+	)
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), "two", 0)
+	cache.Set(IntKey(1), "new one", 0)
+
+	assert.Equal(test, int64(10), cache.Cost())
+
+	cache.Delete(IntKey(2))
+
+	assert.Equal(test, int64(7), cache.Cost())
+}
+
+func TestCache_SetWithCost(test *testing.T) {
+	type bucket struct {
+		key   hashmap.Key
+		value interface{}
+	}
+
+	for _, data := range []struct {
+		name        string
+		maxCost     int64
+		prepare     func(cache Cache)
+		wantBuckets []bucket
+		wantCost    int64
+	}{
+		{
+			name:    "without a maximal cost",
+			maxCost: 0,
+			prepare: func(cache Cache) {
+				cache.SetWithCost(IntKey(1), "one", 0, 10)
+				cache.SetWithCost(IntKey(2), "two", 0, 20)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(1), value: "one"},
+				{key: IntKey(2), value: "two"},
+			},
+			wantCost: 30,
+		},
+		{
+			name:    "with the maximal cost and inserted keys",
+			maxCost: 30,
+			prepare: func(cache Cache) {
+				cache.SetWithCost(IntKey(1), "one", 0, 10)
+				cache.SetWithCost(IntKey(2), "two", 0, 10)
+				cache.SetWithCost(IntKey(3), "three", 0, 20)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(2), value: "two"},
+				{key: IntKey(3), value: "three"},
+			},
+			wantCost: 30,
+		},
+		{
+			name:    "with the maximal cost and an increased cost",
+			maxCost: 30,
+			prepare: func(cache Cache) {
+				cache.SetWithCost(IntKey(1), "one", 0, 10)
+				cache.SetWithCost(IntKey(2), "two", 0, 10)
+				cache.SetWithCost(IntKey(3), "three", 0, 10)
+				cache.SetWithCost(IntKey(3), "new three", 0, 25)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(3), value: "new three"},
+			},
+			wantCost: 25,
+		},
+		{
+			name:    "with the maximal cost and a too large cost",
+			maxCost: 30,
+			prepare: func(cache Cache) {
+				cache.SetWithCost(IntKey(1), "one", 0, 10)
+				cache.SetWithCost(IntKey(2), "two", 0, 10)
+				cache.SetWithCost(IntKey(2), "new two", 0, 31)
+				cache.SetWithCost(IntKey(3), "three", 0, 31)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(1), value: "one"},
+			},
+			wantCost: 10,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock), WithMaxCost(data.maxCost))
+			data.prepare(cache)
+
+			var gotBuckets []bucket
+			cache.Iterate(
+				context.Background(),
+				func(key hashmap.Key, value interface{}) bool {
+					gotBuckets = append(gotBuckets, bucket{key: key, value: value})
+					return true
+				},
+			)
+
+			assert.ElementsMatch(test, data.wantBuckets, gotBuckets)
+			assert.Equal(test, data.wantCost, cache.Cost())
+		})
+	}
+}
+
+func TestCache_Set_withEviction(test *testing.T) {
+	type bucket struct {
+		key   hashmap.Key
+		value interface{}
+	}
+
+	for _, data := range []struct {
+		name        string
+		maxSize     int
+		prepare     func(cache Cache)
+		wantBuckets []bucket
+		wantSize    int64
+	}{
+		{
+			name:    "without a maximal size",
+			maxSize: 0,
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(1), "one", 0)
+				cache.Set(IntKey(2), "two", 0)
+				cache.Set(IntKey(3), "three", 0)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(1), value: "one"},
+				{key: IntKey(2), value: "two"},
+				{key: IntKey(3), value: "three"},
+			},
+			wantSize: 3,
+		},
+		{
+			name:    "with the maximal size and inserted keys",
+			maxSize: 2,
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(1), "one", 0)
+				cache.Set(IntKey(2), "two", 0)
+				cache.Set(IntKey(3), "three", 0)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(2), value: "two"},
+				{key: IntKey(3), value: "three"},
+			},
+			wantSize: 2,
+		},
+		{
+			name:    "with the maximal size and accessed keys",
+			maxSize: 2,
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(1), "one", 0)
+				cache.Set(IntKey(2), "two", 0)
+				cache.Get(IntKey(1)) // nolint: errcheck
+				cache.Set(IntKey(3), "three", 0)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(1), value: "one"},
+				{key: IntKey(3), value: "three"},
+			},
+			wantSize: 2,
+		},
+		{
+			name:    "with the maximal size and replaced keys",
+			maxSize: 2,
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(1), "one", 0)
+				cache.Set(IntKey(2), "two", 0)
+				cache.Set(IntKey(1), "new one", 0)
+				cache.Set(IntKey(3), "three", 0)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(1), value: "new one"},
+				{key: IntKey(3), value: "three"},
+			},
+			wantSize: 2,
+		},
+		{
+			name:    "with the maximal size and deleted keys",
+			maxSize: 2,
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(1), "one", 0)
+				cache.Set(IntKey(2), "two", 0)
+				cache.Delete(IntKey(1))
+				cache.Set(IntKey(3), "three", 0)
+			},
+			wantBuckets: []bucket{
+				{key: IntKey(2), value: "two"},
+				{key: IntKey(3), value: "three"},
+			},
+			wantSize: 2,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock), WithMaxSize(data.maxSize))
+			data.prepare(cache)
+
+			var gotBuckets []bucket
+			cache.Iterate(
+				context.Background(),
+				func(key hashmap.Key, value interface{}) bool {
+					gotBuckets = append(gotBuckets, bucket{key: key, value: value})
+					return true
+				},
+			)
+
+			assert.ElementsMatch(test, data.wantBuckets, gotBuckets)
+			assert.Equal(test, data.wantSize, cache.size.Load())
+		})
+	}
+}
+
+func TestCache_Set_withEvictionPolicy(test *testing.T) {
+	evictionPolicy := new(MockEvictionPolicy)
+	evictionPolicy.On("OnInsert", IntKey(1))
+	evictionPolicy.On("OnInsert", IntKey(2))
+	evictionPolicy.On("OnAccess", IntKey(1))
+	evictionPolicy.On("Victim").Return(IntKey(2), true).Once()
+	evictionPolicy.On("OnDelete", IntKey(2))
+
+	cache := NewCache(
+		WithClock(clock),
+		WithMaxSize(1),
+		WithEvictionPolicy(evictionPolicy),
+	)
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(1), "new one", 0)
+	cache.Set(IntKey(2), "two", 0)
+
+	mock.AssertExpectationsForObjects(test, evictionPolicy)
+	assert.Equal(test, int64(1), cache.size.Load())
+
+	_, err := cache.Get(IntKey(2))
+	assert.Equal(test, ErrKeyMissed, err)
+}
+
+func TestCache_Set_withAdmission(test *testing.T) {
+	evictionPolicy := eviction.NewTinyLFU(1, eviction.TinyLFUWithWindowPercent(0))
+	cache := NewCache(
+		WithClock(clock),
+		WithMaxSize(1),
+		WithEvictionPolicy(evictionPolicy),
+	)
+	cache.Set(IntKey(1), "one", 0)
+	cache.Get(IntKey(1)) // nolint: errcheck
+	cache.Set(IntKey(2), "two", 0)
+
+	gotData, gotErr := cache.Get(IntKey(1))
+	assert.Equal(test, "one", gotData)
+	assert.NoError(test, gotErr)
+
+	_, gotErr = cache.Get(IntKey(2))
+	assert.Equal(test, ErrKeyMissed, gotErr)
+	assert.Equal(test, int64(1), cache.size.Load())
+}
+
 func TestCache_Delete(test *testing.T) {
 	storage := new(MockStorage)
-	storage.On("Delete", NewMockKeyWithID(23))
+	storage.
+		On("Get", IntKey(23)).
+		Return(models.Value{Data: "data", ExpirationTime: time.Time{}}, true)
+	storage.On("Delete", IntKey(23))
 
-	key := NewMockKeyWithID(23)
+	cache := NewCache(WithStorage(storage), WithClock(clock))
+	cache.Delete(IntKey(23))
 
-	cache := Cache{storage, clock}
-	cache.Delete(key)
+	mock.AssertExpectationsForObjects(test, storage)
+}
+
+func TestCache_Len(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), "two", -time.Second)
+	cache.Set(IntKey(1), "one", time.Second)
+	cache.Delete(IntKey(3))
+
+	assert.Equal(test, 2, cache.Len())
+	assert.Equal(test, 1, cache.LiveLen())
+
+	cache.Delete(IntKey(1))
+
+	assert.Equal(test, 1, cache.Len())
+	assert.Equal(test, 0, cache.LiveLen())
+}
+
+func TestCache_Len_withGC(test *testing.T) {
+	for _, data := range []struct {
+		name      string
+		gcFactory GCFactory
+	}{
+		{
+			name: "with the total GC",
+			gcFactory: func(storage hashmap.Storage, clock models.Clock) gc.GC {
+				return gc.NewTotalGC(storage, gc.TotalGCWithClock(clock))
+			},
+		},
+		{
+			name: "with the partial GC",
+			gcFactory: func(storage hashmap.Storage, clock models.Clock) gc.GC {
+				return gc.NewPartialGC(storage, gc.PartialGCWithClock(clock))
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock), WithWeigher(
+				func(key hashmap.Key, data interface{}) int64 { return 1 },
+			))
+			for i := 0; i < 10; i++ {
+				var ttl time.Duration
+				if i%2 == 0 {
+					ttl = -time.Second
+				}
+
+				cache.Set(IntKey(i), i, ttl)
+			}
+
+			gcInstance := data.gcFactory(
+				gcStorage{Storage: cache.storage, cache: cache},
+				cache.clock,
+			)
+			for cache.Len() != cache.LiveLen() {
+				gcInstance.Clean(context.Background())
+			}
+
+			assert.Equal(test, 5, cache.Len())
+			assert.Equal(test, int64(5), cache.Cost())
+		})
+	}
+}
+
+func TestCache_Clear(test *testing.T) {
+	evictionPolicy := eviction.NewLRU()
+	cache := NewCache(
+		WithClock(clock),
+		WithMaxSize(10),
+		WithWeigher(func(key hashmap.Key, data interface{}) int64 { return 1 }),
+		WithEvictionPolicy(evictionPolicy),
+	)
+	for i := 0; i < 10; i++ {
+		cache.Set(IntKey(i), i, 0)
+	}
+
+	cache.Clear()
+	cache.Set(IntKey(23), 23, 0)
+
+	_, gotErr := cache.Get(IntKey(1))
+	assert.Equal(test, ErrKeyMissed, gotErr)
+
+	gotData, gotErr := cache.Get(IntKey(23))
+	assert.Equal(test, 23, gotData)
+	assert.NoError(test, gotErr)
+
+	assert.Equal(test, 1, cache.Len())
+	assert.Equal(test, int64(1), cache.Cost())
+
+	victim, ok := evictionPolicy.Victim()
+	assert.Equal(test, IntKey(23), victim)
+	assert.True(test, ok)
+}
+
+func TestCache_Clear_withConcurrentCalls(test *testing.T) {
+	const keyCount = 100
+
+	cache := NewCache(WithClock(clock))
+	for i := 0; i < keyCount; i++ {
+		cache.Set(IntKey(i), i, 0)
+	}
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(2)
+
+	var isCleared atomic.Bool
+	go func() {
+		defer waitGroup.Done()
+
+		cache.Clear()
+		isCleared.Store(true)
+	}()
+	go func() {
+		defer waitGroup.Done()
+
+		for i := 0; i < keyCount; i++ {
+			cache.Set(IntKey(keyCount+i), i, 0)
+		}
+	}()
+
+	// once a value of the previous generation is missed, all of them are
+	for !isCleared.Load() {
+		results := cache.GetMany([]hashmap.Key{IntKey(0), IntKey(keyCount - 1)})
+		if results[0].Err != nil {
+			_, err := cache.Get(IntKey(keyCount - 1))
+			assert.Equal(test, ErrKeyMissed, err)
+		}
+	}
+	waitGroup.Wait()
 
-	mock.AssertExpectationsForObjects(test, storage, key)
+	assert.Equal(test, cache.LiveLen(), cache.Len())
+	assert.LessOrEqual(test, cache.Len(), keyCount)
 }
 
 func clock() time.Time {
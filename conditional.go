@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// ErrValueRejected ...
+//
+// It's returned when a value isn't set because of the maximal cost
+// or the eviction policy.
+//
+var ErrValueRejected = errors.New("value rejected")
+
+// EqualityFunc ...
+//
+// It should report whether the data are equal.
+//
+type EqualityFunc func(data interface{}, otherData interface{}) bool
+
+// Updater ...
+//
+// It receives the current data of the key and the flag whether it's present
+// and not expired. It should return new data and its time to live. Zero time
+// to live means infinite one. If the keep flag is false, the key is deleted
+// instead.
+//
+// It's called under a lock of the key, so it shouldn't call methods
+// of the cache.
+//
+type Updater func(data interface{}, ok bool) (
+	newData interface{},
+	ttl time.Duration,
+	keep bool,
+)
+
+// SetIfAbsent ...
+//
+// It sets the data only if the key is missed or expired and reports whether
+// the data was set. Zero time to live means infinite one.
+//
+// Like the Set() method, it can reject the data because of the maximal cost
+// or the eviction policy.
+//
+func (cache Cache) SetIfAbsent(
+	key hashmap.Key,
+	data interface{},
+	ttl time.Duration,
+	options ...ValueOption,
+) (ok bool) {
+	value := cache.newValue(key, data, ttl, options)
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		if _, err := transaction.value(); err == nil {
+			return
+		}
+
+		ok = transaction.set(value)
+	})
+
+	return ok
+}
+
+// Replace ...
+//
+// It sets the data only if the key is present, not expired and not negative.
+// Zero time to live means infinite one.
+//
+// If the maximal cost is set and a cost of the value exceeds it,
+// the data isn't set, and the previous data of the key is deleted,
+// like for the SetWithOptions() method.
+//
+// The error can be ErrKeyMissed, ErrKeyExpired, ErrValueRejected or an error
+// of a negative value.
+//
+func (cache Cache) Replace(
+	key hashmap.Key,
+	data interface{},
+	ttl time.Duration,
+	options ...ValueOption,
+) (err error) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		if _, err = transaction.value(); err != nil {
+			return
+		}
+
+		if !transaction.set(cache.newValue(key, data, ttl, options)) {
+			err = ErrValueRejected
+		}
+	})
+
+	return err
+}
+
+// CompareAndSwap ...
+//
+// It sets the new data only if the key is present, not expired and its
+// current data equals the old one, and reports whether the data was set.
+// Zero time to live means infinite one.
+//
+// If the equality function is nil, the reflect.DeepEqual() function is used.
+//
+func (cache Cache) CompareAndSwap(
+	key hashmap.Key,
+	oldData interface{},
+	newData interface{},
+	ttl time.Duration,
+	equal EqualityFunc,
+	options ...ValueOption,
+) (ok bool) {
+	if equal == nil {
+		equal = reflect.DeepEqual
+	}
+
+	value := cache.newValue(key, newData, ttl, options)
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		currentValue, err := transaction.value()
+		if err != nil || !equal(currentValue.Data, oldData) {
+			return
+		}
+
+		ok = transaction.set(value)
+	})
+
+	return ok
+}
+
+// GetAndSet ...
+//
+// It sets the data unconditionally and returns the previous data of the key.
+// Zero time to live means infinite one.
+//
+// The error describes the previous data and can be ErrKeyMissed,
+// ErrKeyExpired or an error of a negative value.
+//
+// If the maximal cost is set and a cost of the value exceeds it,
+// the data isn't set, and the previous data of the key is deleted,
+// like for the SetWithOptions() method. Then the error is ErrValueRejected,
+// but the previous data is still returned.
+//
+func (cache Cache) GetAndSet(
+	key hashmap.Key,
+	data interface{},
+	ttl time.Duration,
+	options ...ValueOption,
+) (previousData interface{}, err error) {
+	value := cache.newValue(key, data, ttl, options)
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		var previousValue models.Value
+		previousValue, err = transaction.value()
+		previousData = previousValue.Data
+
+		if !transaction.set(value) {
+			err = ErrValueRejected
+		}
+	})
+
+	return previousData, err
+}
+
+// GetAndDelete ...
+//
+// It deletes the key and returns its data.
+//
+// The error can be ErrKeyMissed, ErrKeyExpired or an error of a negative
+// value.
+//
+func (cache Cache) GetAndDelete(key hashmap.Key) (data interface{}, err error) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		var value models.Value
+		value, err = transaction.value()
+		data = value.Data
+
+		transaction.delete(RemovalReasonDeleted)
+	})
+
+	return data, err
+}
+
+// Update ...
+//
+// It atomically replaces the data of the key with the result of the updater.
+// See the Updater type for details.
+//
+// If the maximal cost is set and a cost of the new data exceeds it,
+// the data isn't set, and the previous data of the key is deleted,
+// like for the SetWithOptions() method.
+//
+// The error can be ErrValueRejected only.
+//
+func (cache Cache) Update(key hashmap.Key, updater Updater) (err error) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		value, valueErr := transaction.value()
+		newData, ttl, keep := updater(value.Data, valueErr == nil)
+		if !keep {
+			transaction.delete(RemovalReasonDeleted)
+			return
+		}
+
+		if !transaction.set(cache.newValue(key, newData, ttl, nil)) {
+			err = ErrValueRejected
+		}
+	})
+
+	return err
+}
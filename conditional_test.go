@@ -0,0 +1,514 @@
+package cache
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_conditionalSetting(test *testing.T) {
+	type args struct {
+		set func(cache Cache) (ok bool)
+	}
+
+	for _, data := range []struct {
+		name      string
+		prepare   func(cache Cache)
+		args      args
+		wantValue models.Value
+		wantOk    assert.BoolAssertionFunc
+	}{
+		{
+			name:    "SetIfAbsent/success with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.SetIfAbsent(IntKey(23), "data #2", time.Second)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data #2",
+				ExpirationTime: clock().Add(time.Second),
+			},
+			wantOk: assert.True,
+		},
+		{
+			name: "SetIfAbsent/success with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.SetIfAbsent(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{Data: "data #2"},
+			wantOk:    assert.True,
+		},
+		{
+			name: "SetIfAbsent/failure with a present key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.SetIfAbsent(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{Data: "data #1"},
+			wantOk:    assert.False,
+		},
+		{
+			name: "CompareAndSwap/success with the equal data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.CompareAndSwap(IntKey(23), "data #1", "data #2", 0, nil)
+				},
+			},
+			wantValue: models.Value{Data: "data #2"},
+			wantOk:    assert.True,
+		},
+		{
+			name: "CompareAndSwap/success with the equality function",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.CompareAndSwap(
+						IntKey(23),
+						"DATA #1",
+						"data #2",
+						0,
+						func(data interface{}, otherData interface{}) bool {
+							return len(data.(string)) == len(otherData.(string))
+						},
+					)
+				},
+			},
+			wantValue: models.Value{Data: "data #2"},
+			wantOk:    assert.True,
+		},
+		{
+			name: "CompareAndSwap/failure with the different data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.CompareAndSwap(IntKey(23), "data #3", "data #2", 0, nil)
+				},
+			},
+			wantValue: models.Value{Data: "data #1"},
+			wantOk:    assert.False,
+		},
+		{
+			name: "CompareAndSwap/failure with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.CompareAndSwap(IntKey(23), "data #1", "data #2", 0, nil)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data #1",
+				ExpirationTime: clock().Add(-time.Second),
+			},
+			wantOk: assert.False,
+		},
+		{
+			name:    "CompareAndSwap/failure with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				set: func(cache Cache) (ok bool) {
+					return cache.CompareAndSwap(IntKey(23), nil, "data #2", 0, nil)
+				},
+			},
+			wantValue: models.Value{},
+			wantOk:    assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			gotOk := data.args.set(cache)
+
+			gotValue, _ := cache.storage.Get(IntKey(23))
+			if gotValue == nil {
+				gotValue = models.Value{}
+			}
+
+			assert.Equal(test, data.wantValue, gotValue)
+			data.wantOk(test, gotOk)
+		})
+	}
+}
+
+func TestCache_gettingAndSetting(test *testing.T) {
+	type args struct {
+		set func(cache Cache) (data interface{}, err error)
+	}
+
+	for _, data := range []struct {
+		name      string
+		prepare   func(cache Cache)
+		args      args
+		wantValue models.Value
+		wantData  interface{}
+		wantErr   error
+	}{
+		{
+			name:    "Replace/error with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return nil, cache.Replace(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{},
+			wantData:  nil,
+			wantErr:   ErrKeyMissed,
+		},
+		{
+			name: "Replace/error with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return nil, cache.Replace(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data #1",
+				ExpirationTime: clock().Add(-time.Second),
+			},
+			wantData: nil,
+			wantErr:  ErrKeyExpired,
+		},
+		{
+			name: "Replace/success",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return nil, cache.Replace(IntKey(23), "data #2", time.Second)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data #2",
+				ExpirationTime: clock().Add(time.Second),
+			},
+			wantData: nil,
+			wantErr:  nil,
+		},
+		{
+			name:    "GetAndSet/error with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return cache.GetAndSet(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{Data: "data #2"},
+			wantData:  nil,
+			wantErr:   ErrKeyMissed,
+		},
+		{
+			name: "GetAndSet/error with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return cache.GetAndSet(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{Data: "data #2"},
+			wantData:  nil,
+			wantErr:   ErrKeyExpired,
+		},
+		{
+			name: "GetAndSet/success",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return cache.GetAndSet(IntKey(23), "data #2", 0)
+				},
+			},
+			wantValue: models.Value{Data: "data #2"},
+			wantData:  "data #1",
+			wantErr:   nil,
+		},
+		{
+			name:    "GetAndDelete/error with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return cache.GetAndDelete(IntKey(23))
+				},
+			},
+			wantValue: models.Value{},
+			wantData:  nil,
+			wantErr:   ErrKeyMissed,
+		},
+		{
+			name: "GetAndDelete/error with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return cache.GetAndDelete(IntKey(23))
+				},
+			},
+			wantValue: models.Value{},
+			wantData:  nil,
+			wantErr:   ErrKeyExpired,
+		},
+		{
+			name: "GetAndDelete/success",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			args: args{
+				set: func(cache Cache) (data interface{}, err error) {
+					return cache.GetAndDelete(IntKey(23))
+				},
+			},
+			wantValue: models.Value{},
+			wantData:  "data #1",
+			wantErr:   nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			gotData, gotErr := data.args.set(cache)
+
+			gotValue, _ := cache.storage.Get(IntKey(23))
+			if gotValue == nil {
+				gotValue = models.Value{}
+			}
+
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantData, gotData)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestCache_Replace_withExceededMaxCost(test *testing.T) {
+	cache := NewCache(WithClock(clock), WithMaxCost(10))
+	cache.SetWithCost(IntKey(23), "data #1", 0, 5)
+
+	gotErr := cache.Replace(IntKey(23), "data #2", 0, ValueWithCost(15))
+	assert.Equal(test, ErrValueRejected, gotErr)
+
+	_, gotErr = cache.Get(IntKey(23))
+	assert.Equal(test, ErrKeyMissed, gotErr)
+	assert.Equal(test, int64(0), cache.Cost())
+}
+
+func TestCache_GetAndSet_withExceededMaxCost(test *testing.T) {
+	cache := NewCache(WithClock(clock), WithMaxCost(10))
+	cache.SetWithCost(IntKey(23), "data #1", 0, 5)
+
+	gotData, gotErr :=
+		cache.GetAndSet(IntKey(23), "data #2", 0, ValueWithCost(15))
+	assert.Equal(test, "data #1", gotData)
+	assert.Equal(test, ErrValueRejected, gotErr)
+
+	_, gotErr = cache.Get(IntKey(23))
+	assert.Equal(test, ErrKeyMissed, gotErr)
+	assert.Equal(test, int64(0), cache.Cost())
+}
+
+func TestCache_Update(test *testing.T) {
+	type updaterArgs struct {
+		data interface{}
+		ok   bool
+	}
+	type updaterResults struct {
+		newData interface{}
+		ttl     time.Duration
+		keep    bool
+	}
+
+	for _, data := range []struct {
+		name            string
+		prepare         func(cache Cache)
+		updaterResults  updaterResults
+		wantUpdaterArgs updaterArgs
+		wantValue       models.Value
+		wantSize        int64
+	}{
+		{
+			name:            "with a missed key and keeping",
+			prepare:         func(cache Cache) {},
+			updaterResults:  updaterResults{newData: "data #2", ttl: time.Second, keep: true},
+			wantUpdaterArgs: updaterArgs{data: nil, ok: false},
+			wantValue: models.Value{
+				Data:           "data #2",
+				ExpirationTime: clock().Add(time.Second),
+			},
+			wantSize: 1,
+		},
+		{
+			name: "with an expired key and keeping",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			updaterResults:  updaterResults{newData: "data #2", ttl: 0, keep: true},
+			wantUpdaterArgs: updaterArgs{data: nil, ok: false},
+			wantValue:       models.Value{Data: "data #2"},
+			wantSize:        1,
+		},
+		{
+			name: "with a present key and keeping",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			updaterResults:  updaterResults{newData: "data #2", ttl: 0, keep: true},
+			wantUpdaterArgs: updaterArgs{data: "data #1", ok: true},
+			wantValue:       models.Value{Data: "data #2"},
+			wantSize:        1,
+		},
+		{
+			name: "with a present key and without keeping",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			updaterResults:  updaterResults{newData: nil, ttl: 0, keep: false},
+			wantUpdaterArgs: updaterArgs{data: "data #1", ok: true},
+			wantValue:       models.Value{},
+			wantSize:        0,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			var gotUpdaterArgs updaterArgs
+			results := data.updaterResults
+			gotErr := cache.Update(IntKey(23), func(currentData interface{}, ok bool) (
+				newData interface{},
+				ttl time.Duration,
+				keep bool,
+			) {
+				gotUpdaterArgs = updaterArgs{data: currentData, ok: ok}
+				return results.newData, results.ttl, results.keep
+			})
+
+			gotValue, _ := cache.storage.Get(IntKey(23))
+			if gotValue == nil {
+				gotValue = models.Value{}
+			}
+
+			assert.Equal(test, data.wantUpdaterArgs, gotUpdaterArgs)
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantSize, cache.size.Load())
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func TestCache_Update_withExceededMaxCost(test *testing.T) {
+	cache := NewCache(
+		WithClock(clock),
+		WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+			return int64(len(data.(string)))
+		}),
+		WithMaxCost(10),
+	)
+	cache.Set(IntKey(23), "data", 0)
+
+	gotErr := cache.Update(IntKey(23), func(data interface{}, ok bool) (
+		newData interface{},
+		ttl time.Duration,
+		keep bool,
+	) {
+		return strings.Repeat(data.(string), 3), 0, true
+	})
+	assert.Equal(test, ErrValueRejected, gotErr)
+
+	_, gotErr = cache.Get(IntKey(23))
+	assert.Equal(test, ErrKeyMissed, gotErr)
+	assert.Equal(test, int64(0), cache.Cost())
+}
+
+func TestCache_SetIfAbsent_withConcurrentCalls(test *testing.T) {
+	const callCount = 100
+
+	cache := NewCache(WithClock(clock))
+
+	var waitGroup sync.WaitGroup
+	var successCount int32
+	for i := 0; i < callCount; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			if cache.SetIfAbsent(IntKey(23), i, 0) {
+				atomic.AddInt32(&successCount, 1)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, int32(1), successCount)
+	assert.Equal(test, int64(1), cache.size.Load())
+}
+
+func TestCache_Update_withConcurrentCalls(test *testing.T) {
+	const callCount = 100
+
+	cache := NewCache(WithClock(clock))
+	gcStorage := gcStorage{Storage: cache.storage, cache: cache}
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < callCount; i++ {
+		waitGroup.Add(2)
+
+		go func() {
+			defer waitGroup.Done()
+
+			cache.Update(IntKey(23), func(data interface{}, ok bool) (
+				newData interface{},
+				ttl time.Duration,
+				keep bool,
+			) {
+				counter, _ := data.(int)
+				return counter + 1, 0, true
+			}) // nolint: errcheck
+		}()
+		// the key is never expired, so garbage collection shouldn't delete it
+		go func() {
+			defer waitGroup.Done()
+
+			gcStorage.Delete(IntKey(23))
+		}()
+	}
+	waitGroup.Wait()
+
+	gotData, gotErr := cache.Get(IntKey(23))
+	assert.Equal(test, callCount, gotData)
+	assert.NoError(test, gotErr)
+}
@@ -0,0 +1,199 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// ErrNotNumeric ...
+//
+// The NotNumericError type wraps it, so it can be checked
+// via the errors.Is() function.
+//
+var ErrNotNumeric = errors.New("data isn't numeric")
+
+// NotNumericError ...
+//
+// It's returned on incrementing of a value with data of an unsupported type.
+//
+type NotNumericError struct {
+	Data interface{}
+}
+
+// Error ...
+func (err NotNumericError) Error() string {
+	return fmt.Sprintf("%s: %T", ErrNotNumeric, err.Data)
+}
+
+// Unwrap ...
+func (err NotNumericError) Unwrap() error {
+	return ErrNotNumeric
+}
+
+// IncrBy ...
+//
+// It atomically adds the delta to integer data of the key and returns
+// the result, which is stored as int64. A missed or expired key is created
+// with the delta as data and the specified time to live. A present value
+// keeps its current time to live unless the CounterWithTTLReset() option
+// is specified. Zero time to live means infinite one.
+//
+// The data can be of any integer type. Otherwise, the error
+// is NotNumericError. On an overflow, including for unsigned data exceeding
+// the int64 range, the result wraps around.
+//
+// Like the SetWithOptions() method, it can reject the result because
+// of the maximal cost or the eviction policy; then the error
+// is ErrValueRejected.
+//
+func (cache Cache) IncrBy(
+	key hashmap.Key,
+	delta int64,
+	ttl time.Duration,
+	options ...CounterOption,
+) (counter int64, err error) {
+	err = cache.increment(key, ttl, options, func(data interface{}, ok bool) (
+		newData interface{},
+		err error,
+	) {
+		counter = delta
+		if ok {
+			current, isInteger := toInt64(data)
+			if !isInteger {
+				return nil, NotNumericError{Data: data}
+			}
+
+			counter += current
+		}
+
+		return counter, nil
+	})
+
+	return counter, err
+}
+
+// IncrByFloat ...
+//
+// It's the same as the IncrBy() method, but it stores the result as float64.
+// The data can be of any floating-point or integer type.
+//
+func (cache Cache) IncrByFloat(
+	key hashmap.Key,
+	delta float64,
+	ttl time.Duration,
+	options ...CounterOption,
+) (counter float64, err error) {
+	err = cache.increment(key, ttl, options, func(data interface{}, ok bool) (
+		newData interface{},
+		err error,
+	) {
+		counter = delta
+		if ok {
+			current, isNumeric := toFloat64(data)
+			if !isNumeric {
+				return nil, NotNumericError{Data: data}
+			}
+
+			counter += current
+		}
+
+		return counter, nil
+	})
+
+	return counter, err
+}
+
+func (cache Cache) increment(
+	key hashmap.Key,
+	ttl time.Duration,
+	options []CounterOption,
+	add func(data interface{}, ok bool) (newData interface{}, err error),
+) (err error) {
+	var config counterConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		value, valueErr := transaction.value()
+		isPresent := valueErr == nil
+
+		var newData interface{}
+		newData, err = add(value.Data, isPresent)
+		if err != nil {
+			return
+		}
+
+		if !isPresent {
+			if !transaction.set(cache.newValue(key, newData, ttl, nil)) {
+				err = ErrValueRejected
+			}
+
+			return
+		}
+
+		value.Data = newData
+		if cache.weigher != nil {
+			value.Cost = cache.weigher(key, newData)
+		}
+		if config.isTTLReset {
+			value.ExpirationTime = cache.expirationTime(cache.clock(), ttl)
+			value.StaleTime = time.Time{}
+			value.Revalidation = nil
+		}
+
+		value.Touch(cache.clock)
+		if !transaction.set(value) {
+			err = ErrValueRejected
+		}
+	})
+
+	return err
+}
+
+func toInt64(data interface{}) (number int64, ok bool) {
+	switch data := data.(type) {
+	case int:
+		return int64(data), true
+	case int8:
+		return int64(data), true
+	case int16:
+		return int64(data), true
+	case int32:
+		return int64(data), true
+	case int64:
+		return data, true
+	case uint:
+		return int64(data), true
+	case uint8:
+		return int64(data), true
+	case uint16:
+		return int64(data), true
+	case uint32:
+		return int64(data), true
+	case uint64:
+		return int64(data), true
+	default:
+		return 0, false
+	}
+}
+
+func toFloat64(data interface{}) (number float64, ok bool) {
+	switch data := data.(type) {
+	case float32:
+		return float64(data), true
+	case float64:
+		return data, true
+	// unlike smaller unsigned types, these ones can exceed the int64 range
+	case uint:
+		return float64(data), true
+	case uint64:
+		return float64(data), true
+	default:
+		integer, ok := toInt64(data)
+		return float64(integer), ok
+	}
+}
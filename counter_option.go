@@ -0,0 +1,21 @@
+package cache
+
+type counterConfig struct {
+	isTTLReset bool
+}
+
+// CounterOption ...
+type CounterOption func(config *counterConfig)
+
+// CounterWithTTLReset ...
+//
+// It makes an incrementing apply the specified time to live to a present
+// value too. Otherwise, the latter keeps its current time to live.
+//
+// Default: false.
+//
+func CounterWithTTLReset() CounterOption {
+	return func(config *counterConfig) {
+		config.isTTLReset = true
+	}
+}
@@ -0,0 +1,302 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestNotNumericError(test *testing.T) {
+	var err error = NotNumericError{Data: "data"}
+
+	var notNumericErr NotNumericError
+	if assert.True(test, errors.As(err, &notNumericErr)) {
+		assert.Equal(test, "data", notNumericErr.Data)
+	}
+	assert.ErrorIs(test, err, ErrNotNumeric)
+	assert.EqualError(test, err, "data isn't numeric: string")
+}
+
+func TestCache_incrementing(test *testing.T) {
+	type args struct {
+		increment func(cache Cache) (counter interface{}, err error)
+	}
+
+	for _, data := range []struct {
+		name        string
+		prepare     func(cache Cache)
+		args        args
+		wantValue   models.Value
+		wantCounter interface{}
+		wantErr     error
+	}{
+		{
+			name:    "IncrBy/success with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), 5, time.Second)
+				},
+			},
+			wantValue: models.Value{
+				Data:           int64(5),
+				ExpirationTime: clock().Add(time.Second),
+			},
+			wantCounter: int64(5),
+			wantErr:     nil,
+		},
+		{
+			name: "IncrBy/success with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), 10, -time.Second)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), 5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: int64(5)},
+			wantCounter: int64(5),
+			wantErr:     nil,
+		},
+		{
+			name: "IncrBy/success with a present key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), int32(10), time.Minute)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), -5, time.Second)
+				},
+			},
+			wantValue: models.Value{
+				Data:           int64(5),
+				ExpirationTime: clock().Add(time.Minute),
+			},
+			wantCounter: int64(5),
+			wantErr:     nil,
+		},
+		{
+			name: "IncrBy/success with a present key and the TTL reset",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), 10, time.Minute)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), 5, time.Second, CounterWithTTLReset())
+				},
+			},
+			wantValue: models.Value{
+				Data:           int64(15),
+				ExpirationTime: clock().Add(time.Second),
+			},
+			wantCounter: int64(15),
+			wantErr:     nil,
+		},
+		{
+			name: "IncrBy/success with unsigned data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), uint16(10), 0)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), -5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: int64(5)},
+			wantCounter: int64(5),
+			wantErr:     nil,
+		},
+		{
+			name: "IncrBy/error with non-numeric data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), 5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: "data"},
+			wantCounter: int64(5),
+			wantErr:     NotNumericError{Data: "data"},
+		},
+		{
+			name: "IncrBy/error with floating-point data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), 2.5, 0)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrBy(IntKey(23), 5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: 2.5},
+			wantCounter: int64(5),
+			wantErr:     NotNumericError{Data: 2.5},
+		},
+		{
+			name:    "IncrByFloat/success with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrByFloat(IntKey(23), 2.5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: 2.5},
+			wantCounter: 2.5,
+			wantErr:     nil,
+		},
+		{
+			name: "IncrByFloat/success with integer data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), 10, time.Minute)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrByFloat(IntKey(23), 2.5, 0)
+				},
+			},
+			wantValue: models.Value{
+				Data:           12.5,
+				ExpirationTime: clock().Add(time.Minute),
+			},
+			wantCounter: 12.5,
+			wantErr:     nil,
+		},
+		{
+			name: "IncrByFloat/success with floating-point data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), float32(0.5), 0)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrByFloat(IntKey(23), 2.5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: 3.0},
+			wantCounter: 3.0,
+			wantErr:     nil,
+		},
+		{
+			name: "IncrByFloat/success with unsigned data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), uint64(math.MaxUint64), 0)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrByFloat(IntKey(23), 2.5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: math.MaxUint64 + 2.5},
+			wantCounter: math.MaxUint64 + 2.5,
+			wantErr:     nil,
+		},
+		{
+			name: "IncrByFloat/error with non-numeric data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			args: args{
+				increment: func(cache Cache) (counter interface{}, err error) {
+					return cache.IncrByFloat(IntKey(23), 2.5, 0)
+				},
+			},
+			wantValue:   models.Value{Data: "data"},
+			wantCounter: 2.5,
+			wantErr:     NotNumericError{Data: "data"},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			gotCounter, gotErr := data.args.increment(cache)
+
+			gotValue, _ := cache.storage.Get(IntKey(23))
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantCounter, gotCounter)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestCache_IncrBy_withWeigher(test *testing.T) {
+	cache := NewCache(
+		WithClock(clock),
+		WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+			return data.(int64)
+		}),
+	)
+	cache.IncrBy(IntKey(23), 5, 0) // nolint: errcheck
+	counter, err := cache.IncrBy(IntKey(23), 5, 0)
+
+	assert.Equal(test, int64(10), counter)
+	assert.NoError(test, err)
+	assert.Equal(test, int64(10), cache.Cost())
+}
+
+func TestCache_IncrBy_withExceededMaxCost(test *testing.T) {
+	cache := NewCache(
+		WithClock(clock),
+		WithMaxCost(10),
+		WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+			return data.(int64)
+		}),
+	)
+
+	_, err := cache.IncrBy(IntKey(23), 15, 0)
+	assert.Equal(test, ErrValueRejected, err)
+	assert.Equal(test, 0, cache.Len())
+
+	cache.IncrBy(IntKey(42), 5, 0) // nolint: errcheck
+	_, err = cache.IncrBy(IntKey(42), 10, 0)
+	assert.Equal(test, ErrValueRejected, err)
+	assert.Equal(test, 0, cache.Len())
+	assert.Equal(test, int64(0), cache.Cost())
+}
+
+func TestCache_IncrBy_withConcurrentCalls(test *testing.T) {
+	const callCount = 100
+
+	currentTime := clock()
+	cache := NewCache(WithClock(func() time.Time { return currentTime }))
+	for i := 0; i < callCount; i++ {
+		cache.Set(IntKey(i+100), "data", -time.Second)
+	}
+
+	gcInstance := gc.NewPartialGC(
+		gcStorage{Storage: cache.storage, cache: cache},
+		gc.PartialGCWithClock(cache.clock),
+	)
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < callCount; i++ {
+		waitGroup.Add(2)
+
+		go func() {
+			defer waitGroup.Done()
+
+			_, err := cache.IncrBy(IntKey(23), 1, time.Minute)
+			assert.NoError(test, err)
+		}()
+		go func() {
+			defer waitGroup.Done()
+
+			gcInstance.Clean(context.Background())
+		}()
+	}
+	waitGroup.Wait()
+
+	gotData, gotErr := cache.Get(IntKey(23))
+	assert.Equal(test, int64(callCount), gotData)
+	assert.NoError(test, gotErr)
+}
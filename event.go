@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// EventType ...
+//
+// Types are bit flags, so they can be combined for filtering.
+//
+type EventType int
+
+// ...
+const (
+	EventTypeSet EventType = 1 << iota
+	// EventTypeDelete includes deletion via the Clear() method
+	EventTypeDelete
+	EventTypeExpire
+	EventTypeEvict
+)
+
+// String ...
+func (eventType EventType) String() string {
+	var names []string
+	for _, typeName := range []struct {
+		eventType EventType
+		name      string
+	}{
+		{EventTypeSet, "set"},
+		{EventTypeDelete, "delete"},
+		{EventTypeExpire, "expire"},
+		{EventTypeEvict, "evict"},
+	} {
+		if eventType&typeName.eventType != 0 {
+			names = append(names, typeName.name)
+			eventType &^= typeName.eventType
+		}
+	}
+	if eventType != 0 || len(names) == 0 {
+		names = append(names, fmt.Sprintf("EventType(%d)", int(eventType)))
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Event ...
+//
+// The data is the set one for the set event and the removed one for others.
+//
+type Event struct {
+	Type EventType
+	Key  hashmap.Key
+	Data interface{}
+	Time time.Time
+}
+
+// EventFilter ...
+type EventFilter struct {
+	// zero means all the types
+	Types EventType
+	// nil means all the keys
+	Key func(key hashmap.Key) bool
+}
+
+func (filter EventFilter) match(event Event) bool {
+	if filter.Types != 0 && filter.Types&event.Type == 0 {
+		return false
+	}
+
+	return filter.Key == nil || filter.Key(event.Key)
+}
+
+func eventTypeByRemovalReason(reason RemovalReason) (
+	eventType EventType,
+	ok bool,
+) {
+	switch reason {
+	case RemovalReasonDeleted, RemovalReasonCleared:
+		return EventTypeDelete, true
+	case RemovalReasonExpired:
+		return EventTypeExpire, true
+	case RemovalReasonEvicted:
+		return EventTypeEvict, true
+	default:
+		// replacing is reported by the set event
+		return 0, false
+	}
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestEventType_String(test *testing.T) {
+	for _, data := range []struct {
+		name      string
+		eventType EventType
+		want      string
+	}{
+		{
+			name:      "single type",
+			eventType: EventTypeExpire,
+			want:      "expire",
+		},
+		{
+			name:      "several types",
+			eventType: EventTypeSet | EventTypeEvict,
+			want:      "set|evict",
+		},
+		{
+			name:      "unknown type",
+			eventType: EventTypeDelete | 1<<10,
+			want:      "delete|EventType(1024)",
+		},
+		{
+			name:      "zero type",
+			eventType: 0,
+			want:      "EventType(0)",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.eventType.String()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestEventFilter_match(test *testing.T) {
+	type fields struct {
+		types EventType
+		key   func(key hashmap.Key) bool
+	}
+	type args struct {
+		event Event
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		args   args
+		want   assert.BoolAssertionFunc
+	}{
+		{
+			name: "without conditions",
+			fields: fields{
+				types: 0,
+				key:   nil,
+			},
+			args: args{
+				event: Event{Type: EventTypeSet, Key: IntKey(23)},
+			},
+			want: assert.True,
+		},
+		{
+			name: "with matched types",
+			fields: fields{
+				types: EventTypeSet | EventTypeDelete,
+				key:   nil,
+			},
+			args: args{
+				event: Event{Type: EventTypeSet, Key: IntKey(23)},
+			},
+			want: assert.True,
+		},
+		{
+			name: "with unmatched types",
+			fields: fields{
+				types: EventTypeExpire | EventTypeDelete,
+				key:   nil,
+			},
+			args: args{
+				event: Event{Type: EventTypeSet, Key: IntKey(23)},
+			},
+			want: assert.False,
+		},
+		{
+			name: "with a matched key",
+			fields: fields{
+				types: 0,
+				key:   func(key hashmap.Key) bool { return key.(IntKey) > 10 },
+			},
+			args: args{
+				event: Event{Type: EventTypeSet, Key: IntKey(23)},
+			},
+			want: assert.True,
+		},
+		{
+			name: "with an unmatched key",
+			fields: fields{
+				types: EventTypeSet,
+				key:   func(key hashmap.Key) bool { return key.(IntKey) > 100 },
+			},
+			args: args{
+				event: Event{Type: EventTypeSet, Key: IntKey(23)},
+			},
+			want: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			filter := EventFilter{Types: data.fields.types, Key: data.fields.key}
+			got := filter.match(data.args.event)
+
+			data.want(test, got)
+		})
+	}
+}
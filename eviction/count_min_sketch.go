@@ -0,0 +1,65 @@
+package eviction
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+const (
+	countMinSketchDepth = 4
+	maxCounterValue     = 15 // counters are 4-bit as in TinyLFU
+)
+
+type countMinSketch struct {
+	rows [countMinSketchDepth][]uint8
+	mask uint64
+}
+
+func newCountMinSketch(width int) countMinSketch {
+	width = nextPowerOfTwo(width)
+
+	var sketch countMinSketch
+	for index := range sketch.rows {
+		sketch.rows[index] = make([]uint8, width)
+	}
+	sketch.mask = uint64(width - 1)
+
+	return sketch
+}
+
+func (sketch countMinSketch) increment(key hashmap.Key) {
+	for index, row := range sketch.rows {
+		counter := &row[mixHash(key, uint64(index))&sketch.mask]
+		if *counter < maxCounterValue {
+			*counter++
+		}
+	}
+}
+
+func (sketch countMinSketch) estimate(key hashmap.Key) int {
+	estimation := maxCounterValue
+	for index, row := range sketch.rows {
+		if counter := int(row[mixHash(key, uint64(index))&sketch.mask]); counter < estimation {
+			estimation = counter
+		}
+	}
+
+	return estimation
+}
+
+// it halves all counters, so that old frequencies fade away
+func (sketch countMinSketch) age() {
+	for _, row := range sketch.rows {
+		for index := range row {
+			row[index] /= 2
+		}
+	}
+}
+
+func nextPowerOfTwo(number int) int {
+	power := 1
+	for power < number {
+		power *= 2
+	}
+
+	return power
+}
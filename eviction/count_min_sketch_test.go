@@ -0,0 +1,41 @@
+package eviction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_countMinSketch(test *testing.T) {
+	sketch := newCountMinSketch(10)
+	assert.Len(test, sketch.rows[0], 16)
+
+	for i := 0; i < 5; i++ {
+		sketch.increment(IntKey(1))
+	}
+	for i := 0; i < 2*maxCounterValue; i++ {
+		sketch.increment(IntKey(2))
+	}
+
+	assert.Equal(test, 5, sketch.estimate(IntKey(1)))
+	assert.Equal(test, maxCounterValue, sketch.estimate(IntKey(2)))
+
+	sketch.age()
+
+	assert.Equal(test, 2, sketch.estimate(IntKey(1)))
+	assert.Equal(test, maxCounterValue/2, sketch.estimate(IntKey(2)))
+}
+
+func Test_nextPowerOfTwo(test *testing.T) {
+	for _, data := range []struct {
+		number int
+		want   int
+	}{
+		{number: 0, want: 1},
+		{number: 1, want: 1},
+		{number: 5, want: 8},
+		{number: 16, want: 16},
+	} {
+		assert.Equal(test, data.want, nextPowerOfTwo(data.number))
+	}
+}
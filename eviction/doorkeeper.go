@@ -0,0 +1,61 @@
+package eviction
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+const (
+	doorkeeperHashCount = 3
+	// seeds of the doorkeeper shouldn't intersect with ones of the sketch
+	doorkeeperSeedOffset = countMinSketchDepth
+)
+
+// it's a Bloom filter that stops keys seen only once from polluting
+// the sketch
+type doorkeeper struct {
+	bits []uint64
+	mask uint64
+}
+
+func newDoorkeeper(bitCount int) doorkeeper {
+	bitCount = nextPowerOfTwo(bitCount)
+	if bitCount < 64 {
+		bitCount = 64
+	}
+
+	return doorkeeper{
+		bits: make([]uint64, bitCount/64),
+		mask: uint64(bitCount - 1),
+	}
+}
+
+func (doorkeeper doorkeeper) contains(key hashmap.Key) bool {
+	for index := 0; index < doorkeeperHashCount; index++ {
+		bit := mixHash(key, uint64(doorkeeperSeedOffset+index)) & doorkeeper.mask
+		if doorkeeper.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// it returns true if the key was already present
+func (doorkeeper doorkeeper) add(key hashmap.Key) bool {
+	wasPresent := true
+	for index := 0; index < doorkeeperHashCount; index++ {
+		bit := mixHash(key, uint64(doorkeeperSeedOffset+index)) & doorkeeper.mask
+		if doorkeeper.bits[bit/64]&(1<<(bit%64)) == 0 {
+			doorkeeper.bits[bit/64] |= 1 << (bit % 64)
+			wasPresent = false
+		}
+	}
+
+	return wasPresent
+}
+
+func (doorkeeper doorkeeper) reset() {
+	for index := range doorkeeper.bits {
+		doorkeeper.bits[index] = 0
+	}
+}
@@ -0,0 +1,21 @@
+package eviction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_doorkeeper(test *testing.T) {
+	doorkeeper := newDoorkeeper(10)
+	assert.Len(test, doorkeeper.bits, 1)
+
+	assert.False(test, doorkeeper.contains(IntKey(1)))
+	assert.False(test, doorkeeper.add(IntKey(1)))
+	assert.True(test, doorkeeper.contains(IntKey(1)))
+	assert.True(test, doorkeeper.add(IntKey(1)))
+
+	doorkeeper.reset()
+
+	assert.False(test, doorkeeper.contains(IntKey(1)))
+}
@@ -0,0 +1,32 @@
+package eviction
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+type IntKey int
+
+func (key IntKey) Hash() int {
+	hash := fnv.New32()
+	binary.Write(hash, binary.LittleEndian, int32(key)) // nolint: errcheck
+
+	return int(hash.Sum32())
+}
+
+func (key IntKey) Equals(other hashmap.Key) bool {
+	return key == other.(IntKey)
+}
+
+// it always returns the same hash to check collisions
+type CollidingKey int
+
+func (key CollidingKey) Hash() int {
+	return 23
+}
+
+func (key CollidingKey) Equals(other hashmap.Key) bool {
+	return key == other.(CollidingKey)
+}
@@ -0,0 +1,56 @@
+package eviction
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+const (
+	sampleSizeFactor     = 10
+	doorkeeperBitsFactor = 8
+	minSketchCapacity    = 16
+)
+
+// it combines the doorkeeper and the count-min sketch with periodic aging
+// as described in the TinyLFU paper: https://arxiv.org/abs/1512.00727
+type frequencySketch struct {
+	counters   countMinSketch
+	doorkeeper doorkeeper
+
+	sampleSize    int
+	additionCount int
+}
+
+func newFrequencySketch(capacity int) *frequencySketch {
+	if capacity < minSketchCapacity {
+		capacity = minSketchCapacity
+	}
+
+	return &frequencySketch{
+		counters:   newCountMinSketch(capacity),
+		doorkeeper: newDoorkeeper(capacity * doorkeeperBitsFactor),
+
+		sampleSize: capacity * sampleSizeFactor,
+	}
+}
+
+func (sketch *frequencySketch) increment(key hashmap.Key) {
+	if sketch.doorkeeper.add(key) {
+		sketch.counters.increment(key)
+	}
+
+	sketch.additionCount++
+	if sketch.additionCount >= sketch.sampleSize {
+		sketch.counters.age()
+		sketch.doorkeeper.reset()
+		sketch.additionCount = 0
+	}
+}
+
+func (sketch *frequencySketch) estimate(key hashmap.Key) int {
+	estimation := sketch.counters.estimate(key)
+	if sketch.doorkeeper.contains(key) {
+		estimation++
+	}
+
+	return estimation
+}
@@ -0,0 +1,28 @@
+package eviction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_frequencySketch(test *testing.T) {
+	sketch := newFrequencySketch(1)
+	assert.Equal(test, minSketchCapacity*sampleSizeFactor, sketch.sampleSize)
+
+	// the first access is remembered only by the doorkeeper
+	sketch.increment(IntKey(1))
+	assert.Equal(test, 0, sketch.counters.estimate(IntKey(1)))
+	assert.Equal(test, 1, sketch.estimate(IntKey(1)))
+
+	for i := 0; i < 5; i++ {
+		sketch.increment(IntKey(1))
+	}
+	assert.Equal(test, 6, sketch.estimate(IntKey(1)))
+
+	// trigger the aging
+	for sketch.additionCount != 0 {
+		sketch.increment(IntKey(2))
+	}
+	assert.Equal(test, 2, sketch.estimate(IntKey(1)))
+}
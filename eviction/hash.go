@@ -0,0 +1,14 @@
+package eviction
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// it's based on the finalizer of the SplitMix64 generator; see for details:
+// https://prng.di.unimi.it/splitmix64.c
+func mixHash(key hashmap.Key, seed uint64) uint64 {
+	hash := uint64(key.Hash()) + seed*0x9e3779b97f4a7c15
+	hash = (hash ^ hash>>30) * 0xbf58476d1ce4e5b9
+	hash = (hash ^ hash>>27) * 0x94d049bb133111eb
+	return hash ^ hash>>31
+}
@@ -0,0 +1,56 @@
+package eviction
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+type keyIndexEntry[V any] struct {
+	key   hashmap.Key
+	value V
+}
+
+// it's indexed by key hashes, because the hashmap.Key interface
+// doesn't guarantee that its implementations are comparable
+type keyIndex[V any] map[int][]keyIndexEntry[V]
+
+func (index keyIndex[V]) get(key hashmap.Key) (value V, ok bool) {
+	for _, entry := range index[key.Hash()] {
+		if entry.key.Equals(key) {
+			return entry.value, true
+		}
+	}
+
+	return value, false
+}
+
+func (index keyIndex[V]) set(key hashmap.Key, value V) {
+	hash := key.Hash()
+	entries := index[hash]
+	for entryIndex, entry := range entries {
+		if entry.key.Equals(key) {
+			entries[entryIndex].value = value
+			return
+		}
+	}
+
+	index[hash] = append(entries, keyIndexEntry[V]{key: key, value: value})
+}
+
+func (index keyIndex[V]) delete(key hashmap.Key) {
+	hash := key.Hash()
+	entries := index[hash]
+	for entryIndex, entry := range entries {
+		if !entry.key.Equals(key) {
+			continue
+		}
+
+		entries = append(entries[:entryIndex:entryIndex], entries[entryIndex+1:]...)
+		if len(entries) != 0 {
+			index[hash] = entries
+		} else {
+			delete(index, hash)
+		}
+
+		return
+	}
+}
@@ -0,0 +1,33 @@
+package eviction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_keyIndex(test *testing.T) {
+	index := make(keyIndex[string])
+	index.set(CollidingKey(1), "one")
+	index.set(CollidingKey(2), "two")
+	index.set(CollidingKey(3), "three")
+	index.set(CollidingKey(2), "new two")
+	index.delete(CollidingKey(1))
+	index.delete(CollidingKey(4))
+
+	gotValue, gotOk := index.get(CollidingKey(1))
+	assert.Equal(test, "", gotValue)
+	assert.False(test, gotOk)
+
+	gotValue, gotOk = index.get(CollidingKey(2))
+	assert.Equal(test, "new two", gotValue)
+	assert.True(test, gotOk)
+
+	gotValue, gotOk = index.get(CollidingKey(3))
+	assert.Equal(test, "three", gotValue)
+	assert.True(test, gotOk)
+
+	index.delete(CollidingKey(2))
+	index.delete(CollidingKey(3))
+	assert.Empty(test, index)
+}
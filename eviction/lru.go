@@ -0,0 +1,73 @@
+package eviction
+
+import (
+	"container/list"
+	"sync"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// LRU ...
+//
+// It evicts the least recently used key.
+//
+type LRU struct {
+	lock    sync.Mutex
+	entries *list.List
+	index   keyIndex[*list.Element]
+}
+
+// NewLRU ...
+func NewLRU() *LRU {
+	return &LRU{
+		entries: list.New(),
+		index:   make(keyIndex[*list.Element]),
+	}
+}
+
+// OnAccess ...
+func (lru *LRU) OnAccess(key hashmap.Key) {
+	lru.lock.Lock()
+	defer lru.lock.Unlock()
+
+	if element, ok := lru.index.get(key); ok {
+		lru.entries.MoveToFront(element)
+	}
+}
+
+// OnInsert ...
+func (lru *LRU) OnInsert(key hashmap.Key) {
+	lru.lock.Lock()
+	defer lru.lock.Unlock()
+
+	if element, ok := lru.index.get(key); ok {
+		lru.entries.MoveToFront(element)
+		return
+	}
+
+	lru.index.set(key, lru.entries.PushFront(key))
+}
+
+// OnDelete ...
+func (lru *LRU) OnDelete(key hashmap.Key) {
+	lru.lock.Lock()
+	defer lru.lock.Unlock()
+
+	if element, ok := lru.index.get(key); ok {
+		lru.entries.Remove(element)
+		lru.index.delete(key)
+	}
+}
+
+// Victim ...
+func (lru *LRU) Victim() (key hashmap.Key, ok bool) {
+	lru.lock.Lock()
+	defer lru.lock.Unlock()
+
+	element := lru.entries.Back()
+	if element == nil {
+		return nil, false
+	}
+
+	return element.Value.(hashmap.Key), true
+}
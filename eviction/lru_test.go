@@ -0,0 +1,89 @@
+package eviction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestLRU(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		prepare    func(lru *LRU)
+		wantVictim hashmap.Key
+		wantOk     assert.BoolAssertionFunc
+	}{
+		{
+			name:       "without keys",
+			prepare:    func(lru *LRU) {},
+			wantVictim: nil,
+			wantOk:     assert.False,
+		},
+		{
+			name: "with inserted keys",
+			prepare: func(lru *LRU) {
+				lru.OnInsert(IntKey(1))
+				lru.OnInsert(IntKey(2))
+				lru.OnInsert(IntKey(3))
+			},
+			wantVictim: IntKey(1),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with accessed keys",
+			prepare: func(lru *LRU) {
+				lru.OnInsert(IntKey(1))
+				lru.OnInsert(IntKey(2))
+				lru.OnInsert(IntKey(3))
+				lru.OnAccess(IntKey(1))
+				lru.OnAccess(IntKey(4))
+			},
+			wantVictim: IntKey(2),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with reinserted keys",
+			prepare: func(lru *LRU) {
+				lru.OnInsert(IntKey(1))
+				lru.OnInsert(IntKey(2))
+				lru.OnInsert(IntKey(1))
+			},
+			wantVictim: IntKey(2),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with deleted keys",
+			prepare: func(lru *LRU) {
+				lru.OnInsert(IntKey(1))
+				lru.OnInsert(IntKey(2))
+				lru.OnInsert(IntKey(3))
+				lru.OnDelete(IntKey(1))
+				lru.OnDelete(IntKey(4))
+			},
+			wantVictim: IntKey(2),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with all keys deleted",
+			prepare: func(lru *LRU) {
+				lru.OnInsert(IntKey(1))
+				lru.OnInsert(IntKey(2))
+				lru.OnDelete(IntKey(2))
+				lru.OnDelete(IntKey(1))
+			},
+			wantVictim: nil,
+			wantOk:     assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			lru := NewLRU()
+			data.prepare(lru)
+
+			gotVictim, gotOk := lru.Victim()
+
+			assert.Equal(test, data.wantVictim, gotVictim)
+			data.wantOk(test, gotOk)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package eviction
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+//go:generate mockery -name=Policy -inpkg -case=underscore -testonly
+
+// Policy ...
+//
+// Its implementations should be safe for concurrent access.
+//
+type Policy interface {
+	// OnAccess is called when a present key is got or replaced.
+	OnAccess(key hashmap.Key)
+	// OnInsert is called when a missed key is set.
+	OnInsert(key hashmap.Key)
+	// OnDelete is called when a key is deleted for any reason, including
+	// eviction. It can be called for a key unknown to the policy.
+	OnDelete(key hashmap.Key)
+	// Victim should return a key to evict without forgetting it,
+	// OnDelete will be called for it.
+	Victim() (key hashmap.Key, ok bool)
+}
+
+// Admitter ...
+//
+// It's an optional interface for an implementation of the Policy interface.
+//
+type Admitter interface {
+	// Admit is called before setting a missed key into a full cache.
+	// If it returns false, the key isn't set.
+	Admit(key hashmap.Key) bool
+}
@@ -0,0 +1,238 @@
+package eviction
+
+import (
+	"container/list"
+	"sync"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+type segment int
+
+const (
+	windowSegment segment = iota
+	probationSegment
+	protectedSegment
+)
+
+type tinyLFUEntry struct {
+	key     hashmap.Key
+	segment segment
+}
+
+// TinyLFU ...
+//
+// It implements the Window-TinyLFU policy. See for details:
+// https://arxiv.org/abs/1512.00727
+//
+// New keys get into the admission window (a small LRU). Keys leaving it
+// are admitted into the main region (a segmented LRU) only if they are
+// estimated as more frequent than the eviction candidate of the latter.
+//
+type TinyLFU struct {
+	capacity         int
+	windowPercent    float64
+	protectedPercent float64
+
+	lock               sync.Mutex
+	windowCapacity     int
+	protectedCapacity  int
+	sketch             *frequencySketch
+	segments           [protectedSegment + 1]*list.List
+	index              keyIndex[*list.Element]
+	admissionCandidate *list.Element
+}
+
+// NewTinyLFU ...
+//
+// The capacity should be equal to the maximal size of the cache.
+//
+func NewTinyLFU(capacity int, options ...TinyLFUOption) *TinyLFU {
+	tinyLFU := &TinyLFU{
+		capacity: capacity,
+
+		// default options
+		windowPercent:    defaultWindowPercent,
+		protectedPercent: defaultProtectedPercent,
+	}
+	for _, option := range options {
+		option(tinyLFU)
+	}
+
+	if tinyLFU.windowPercent > 0 {
+		tinyLFU.windowCapacity = int(float64(capacity) * tinyLFU.windowPercent)
+		if tinyLFU.windowCapacity < 1 {
+			tinyLFU.windowCapacity = 1
+		}
+	}
+	mainCapacity := capacity - tinyLFU.windowCapacity
+	tinyLFU.protectedCapacity = int(float64(mainCapacity) * tinyLFU.protectedPercent)
+
+	tinyLFU.sketch = newFrequencySketch(capacity)
+	for index := range tinyLFU.segments {
+		tinyLFU.segments[index] = list.New()
+	}
+	tinyLFU.index = make(keyIndex[*list.Element])
+
+	return tinyLFU
+}
+
+// Admit ...
+//
+// It's called only for a missed key being set into a full cache.
+//
+// If the admission window is present, all keys are admitted into it.
+// Otherwise, a key is admitted if it's estimated as more frequent than
+// the eviction candidate.
+//
+func (tinyLFU *TinyLFU) Admit(key hashmap.Key) bool {
+	tinyLFU.lock.Lock()
+	defer tinyLFU.lock.Unlock()
+
+	if tinyLFU.windowCapacity > 0 {
+		return true
+	}
+
+	victim := tinyLFU.mainVictim()
+	if victim == nil ||
+		tinyLFU.sketch.estimate(key) >= tinyLFU.sketch.estimate(victim.key) {
+		return true
+	}
+
+	// a rejected key doesn't reach the OnInsert() method,
+	// so its access should be counted here
+	tinyLFU.sketch.increment(key)
+	return false
+}
+
+// OnAccess ...
+func (tinyLFU *TinyLFU) OnAccess(key hashmap.Key) {
+	tinyLFU.lock.Lock()
+	defer tinyLFU.lock.Unlock()
+
+	tinyLFU.sketch.increment(key)
+
+	element, ok := tinyLFU.index.get(key)
+	if !ok {
+		return
+	}
+
+	entry := element.Value.(*tinyLFUEntry)
+	switch entry.segment {
+	case windowSegment, protectedSegment:
+		tinyLFU.segments[entry.segment].MoveToFront(element)
+	case probationSegment:
+		if element == tinyLFU.admissionCandidate {
+			tinyLFU.admissionCandidate = nil
+		}
+
+		tinyLFU.moveToSegment(element, protectedSegment)
+		if tinyLFU.segments[protectedSegment].Len() > tinyLFU.protectedCapacity {
+			demoted := tinyLFU.segments[protectedSegment].Back()
+			tinyLFU.moveToSegment(demoted, probationSegment)
+		}
+	}
+}
+
+// OnInsert ...
+func (tinyLFU *TinyLFU) OnInsert(key hashmap.Key) {
+	tinyLFU.lock.Lock()
+	defer tinyLFU.lock.Unlock()
+
+	if _, ok := tinyLFU.index.get(key); ok {
+		return
+	}
+
+	tinyLFU.sketch.increment(key)
+
+	if tinyLFU.windowCapacity == 0 {
+		tinyLFU.pushToSegment(key, probationSegment)
+		return
+	}
+
+	tinyLFU.pushToSegment(key, windowSegment)
+	if tinyLFU.segments[windowSegment].Len() > tinyLFU.windowCapacity {
+		candidate := tinyLFU.segments[windowSegment].Back()
+		tinyLFU.admissionCandidate =
+			tinyLFU.moveToSegment(candidate, probationSegment)
+	}
+}
+
+// OnDelete ...
+func (tinyLFU *TinyLFU) OnDelete(key hashmap.Key) {
+	tinyLFU.lock.Lock()
+	defer tinyLFU.lock.Unlock()
+
+	element, ok := tinyLFU.index.get(key)
+	if !ok {
+		return
+	}
+
+	// after an eviction, the candidate either is evicted or is admitted
+	tinyLFU.admissionCandidate = nil
+
+	entry := element.Value.(*tinyLFUEntry)
+	tinyLFU.segments[entry.segment].Remove(element)
+	tinyLFU.index.delete(key)
+}
+
+// Victim ...
+//
+// It prefers the admission candidate or the LRU key of the probation segment,
+// whichever is estimated as less frequent.
+//
+func (tinyLFU *TinyLFU) Victim() (key hashmap.Key, ok bool) {
+	tinyLFU.lock.Lock()
+	defer tinyLFU.lock.Unlock()
+
+	victim := tinyLFU.mainVictim()
+	if victim == nil {
+		element := tinyLFU.segments[windowSegment].Back()
+		if element == nil {
+			return nil, false
+		}
+
+		victim = element.Value.(*tinyLFUEntry)
+	}
+
+	candidate := tinyLFU.admissionCandidate
+	if candidate != nil {
+		candidateEntry := candidate.Value.(*tinyLFUEntry)
+		if candidateEntry != victim &&
+			tinyLFU.sketch.estimate(candidateEntry.key) <=
+				tinyLFU.sketch.estimate(victim.key) {
+			return candidateEntry.key, true
+		}
+	}
+
+	return victim.key, true
+}
+
+func (tinyLFU *TinyLFU) mainVictim() *tinyLFUEntry {
+	for _, segment := range []segment{probationSegment, protectedSegment} {
+		if element := tinyLFU.segments[segment].Back(); element != nil {
+			return element.Value.(*tinyLFUEntry)
+		}
+	}
+
+	return nil
+}
+
+func (tinyLFU *TinyLFU) pushToSegment(key hashmap.Key, segment segment) {
+	entry := &tinyLFUEntry{key: key, segment: segment}
+	tinyLFU.index.set(key, tinyLFU.segments[segment].PushFront(entry))
+}
+
+func (tinyLFU *TinyLFU) moveToSegment(
+	element *list.Element,
+	segment segment,
+) *list.Element {
+	entry := element.Value.(*tinyLFUEntry)
+	tinyLFU.segments[entry.segment].Remove(element)
+
+	entry.segment = segment
+	movedElement := tinyLFU.segments[segment].PushFront(entry)
+	tinyLFU.index.set(entry.key, movedElement)
+
+	return movedElement
+}
@@ -0,0 +1,34 @@
+package eviction
+
+const (
+	defaultWindowPercent    = 0.01
+	defaultProtectedPercent = 0.8
+)
+
+// TinyLFUOption ...
+type TinyLFUOption func(tinyLFU *TinyLFU)
+
+// TinyLFUWithWindowPercent ...
+//
+// It's a part of the capacity for the admission window. Zero means that
+// the admission filter is applied to keys being set directly.
+//
+// Default: 0.01.
+//
+func TinyLFUWithWindowPercent(windowPercent float64) TinyLFUOption {
+	return func(tinyLFU *TinyLFU) {
+		tinyLFU.windowPercent = windowPercent
+	}
+}
+
+// TinyLFUWithProtectedPercent ...
+//
+// It's a part of the main region for its protected segment.
+//
+// Default: 0.8.
+//
+func TinyLFUWithProtectedPercent(protectedPercent float64) TinyLFUOption {
+	return func(tinyLFU *TinyLFU) {
+		tinyLFU.protectedPercent = protectedPercent
+	}
+}
@@ -0,0 +1,200 @@
+package eviction
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestNewTinyLFU(test *testing.T) {
+	for _, data := range []struct {
+		name                  string
+		capacity              int
+		options               []TinyLFUOption
+		wantWindowCapacity    int
+		wantProtectedCapacity int
+	}{
+		{
+			name:                  "with default options",
+			capacity:              1000,
+			options:               nil,
+			wantWindowCapacity:    10,
+			wantProtectedCapacity: 792,
+		},
+		{
+			name:                  "with a small capacity",
+			capacity:              10,
+			options:               nil,
+			wantWindowCapacity:    1,
+			wantProtectedCapacity: 7,
+		},
+		{
+			name:     "with set options",
+			capacity: 1000,
+			options: []TinyLFUOption{
+				TinyLFUWithWindowPercent(0.2),
+				TinyLFUWithProtectedPercent(0.5),
+			},
+			wantWindowCapacity:    200,
+			wantProtectedCapacity: 400,
+		},
+		{
+			name:                  "without the window",
+			capacity:              1000,
+			options:               []TinyLFUOption{TinyLFUWithWindowPercent(0)},
+			wantWindowCapacity:    0,
+			wantProtectedCapacity: 800,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewTinyLFU(data.capacity, data.options...)
+
+			assert.Equal(test, data.wantWindowCapacity, got.windowCapacity)
+			assert.Equal(test, data.wantProtectedCapacity, got.protectedCapacity)
+		})
+	}
+}
+
+func TestTinyLFU_Victim(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		prepare    func(tinyLFU *TinyLFU)
+		wantVictim hashmap.Key
+		wantOk     assert.BoolAssertionFunc
+	}{
+		{
+			name:       "without keys",
+			prepare:    func(tinyLFU *TinyLFU) {},
+			wantVictim: nil,
+			wantOk:     assert.False,
+		},
+		{
+			name: "with keys in the window only",
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+			},
+			wantVictim: IntKey(1),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with a rare admission candidate",
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+				tinyLFU.OnInsert(IntKey(2))
+				tinyLFU.OnAccess(IntKey(1))
+				tinyLFU.OnAccess(IntKey(1))
+				tinyLFU.OnAccess(IntKey(1))
+
+				tinyLFU.OnInsert(IntKey(3))
+			},
+			wantVictim: IntKey(2),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with a frequent admission candidate",
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+				tinyLFU.OnInsert(IntKey(2))
+				tinyLFU.OnAccess(IntKey(2))
+				tinyLFU.OnAccess(IntKey(2))
+				tinyLFU.OnAccess(IntKey(2))
+
+				tinyLFU.OnInsert(IntKey(3))
+			},
+			wantVictim: IntKey(1),
+			wantOk:     assert.True,
+		},
+		{
+			name: "with protected keys",
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+				tinyLFU.OnInsert(IntKey(2))
+				tinyLFU.OnInsert(IntKey(3))
+				tinyLFU.OnAccess(IntKey(1))
+				tinyLFU.OnAccess(IntKey(2))
+			},
+			wantVictim: IntKey(1),
+			wantOk:     assert.True,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			tinyLFU := NewTinyLFU(4, TinyLFUWithProtectedPercent(0.5))
+			data.prepare(tinyLFU)
+
+			gotVictim, gotOk := tinyLFU.Victim()
+
+			assert.Equal(test, data.wantVictim, gotVictim)
+			data.wantOk(test, gotOk)
+		})
+	}
+}
+
+func TestTinyLFU_Admit(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		options []TinyLFUOption
+		prepare func(tinyLFU *TinyLFU)
+		want    assert.BoolAssertionFunc
+	}{
+		{
+			name:    "with the window",
+			options: nil,
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+				tinyLFU.OnAccess(IntKey(1))
+			},
+			want: assert.True,
+		},
+		{
+			name:    "without the window and keys",
+			options: []TinyLFUOption{TinyLFUWithWindowPercent(0)},
+			prepare: func(tinyLFU *TinyLFU) {},
+			want:    assert.True,
+		},
+		{
+			name:    "without the window and with a frequent victim",
+			options: []TinyLFUOption{TinyLFUWithWindowPercent(0)},
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+				tinyLFU.OnAccess(IntKey(1))
+			},
+			want: assert.False,
+		},
+		{
+			name:    "without the window and with a frequent key",
+			options: []TinyLFUOption{TinyLFUWithWindowPercent(0)},
+			prepare: func(tinyLFU *TinyLFU) {
+				tinyLFU.OnInsert(IntKey(1))
+				tinyLFU.Admit(IntKey(2))
+				tinyLFU.Admit(IntKey(2))
+				tinyLFU.Admit(IntKey(2))
+			},
+			want: assert.True,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			tinyLFU := NewTinyLFU(1, data.options...)
+			data.prepare(tinyLFU)
+
+			got := tinyLFU.Admit(IntKey(2))
+
+			data.want(test, got)
+		})
+	}
+}
+
+func TestTinyLFU_OnDelete(test *testing.T) {
+	tinyLFU := NewTinyLFU(4)
+	tinyLFU.OnInsert(IntKey(1))
+	tinyLFU.OnInsert(IntKey(2))
+	tinyLFU.OnAccess(IntKey(1))
+	tinyLFU.OnDelete(IntKey(1))
+	tinyLFU.OnDelete(IntKey(2))
+
+	for _, segment := range tinyLFU.segments {
+		assert.Zero(test, segment.Len())
+	}
+	assert.Empty(test, tinyLFU.index)
+	assert.Nil(test, tinyLFU.admissionCandidate)
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/thewizardplusplus/go-cache/eviction"
+)
+
+const (
+	evictionKeySpace  = 1e5
+	evictionCacheSize = 1e3
+)
+
+type keyGenerator func() int
+
+func BenchmarkCacheEviction(benchmark *testing.B) {
+	for _, workload := range []struct {
+		name         string
+		newGenerator func(random *rand.Rand) keyGenerator
+	}{
+		{
+			name:         "Zipf",
+			newGenerator: newZipfGenerator,
+		},
+		{
+			name: "ZipfWithScans",
+			newGenerator: func(random *rand.Rand) keyGenerator {
+				zipfGenerator := newZipfGenerator(random)
+
+				// each key of scans is read once only
+				var accessCount, scanKey int
+				return func() int {
+					accessCount++
+					if accessCount%1000 < 400 {
+						scanKey++
+						return evictionKeySpace + scanKey
+					}
+
+					return zipfGenerator()
+				}
+			},
+		},
+	} {
+		for _, policy := range []struct {
+			name      string
+			newPolicy func() eviction.Policy
+		}{
+			{
+				name: "LRU",
+				newPolicy: func() eviction.Policy {
+					return eviction.NewLRU()
+				},
+			},
+			{
+				name: "TinyLFU",
+				newPolicy: func() eviction.Policy {
+					return eviction.NewTinyLFU(evictionCacheSize)
+				},
+			},
+		} {
+			name := fmt.Sprintf("%s/%s", workload.name, policy.name)
+			benchmark.Run(name, func(benchmark *testing.B) {
+				cache := NewCache(
+					WithMaxSize(evictionCacheSize),
+					WithEvictionPolicy(policy.newPolicy()),
+				)
+				generator := workload.newGenerator(rand.New(rand.NewSource(1)))
+
+				var hitCount int
+				for i := 0; i < benchmark.N; i++ {
+					key := IntKey(generator())
+					if _, err := cache.Get(key); err == nil {
+						hitCount++
+						continue
+					}
+
+					cache.Set(key, int(key), 0)
+				}
+
+				benchmark.ReportMetric(
+					float64(hitCount)/float64(benchmark.N),
+					"hit-ratio",
+				)
+			})
+		}
+	}
+}
+
+func newZipfGenerator(random *rand.Rand) keyGenerator {
+	zipf := rand.NewZipf(random, 1.01, 1, evictionKeySpace-1)
+	return func() int {
+		return int(zipf.Uint64())
+	}
+}
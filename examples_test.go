@@ -22,7 +22,8 @@ func (key StringKey) Hash() int {
 }
 
 func (key StringKey) Equals(other hashmap.Key) bool {
-	return key == other.(StringKey)
+	otherKey, ok := other.(StringKey)
+	return ok && key == otherKey
 }
 
 const (
@@ -31,11 +32,10 @@ const (
 )
 
 func ExampleNewCache() {
-	storage := hashmap.NewConcurrentHashMap()
-	gcInstance := gc.NewPartialGC(storage)
+	timeZones := cache.NewCache()
+	gcInstance := gc.NewPartialGC(timeZones.GCStorage())
 	go gc.Run(context.Background(), gcInstance, gcPeriod)
 
-	timeZones := cache.NewCache(cache.WithStorage(storage))
 	timeZones.Set(StringKey("EST"), -5*60*60, exampleDelay/2)
 	timeZones.Set(StringKey("CST"), -6*60*60, exampleDelay/2)
 	timeZones.Set(StringKey("MST"), -7*60*60, exampleDelay/2)
@@ -103,6 +103,37 @@ func Test_iterator_handleIteration(test *testing.T) {
 			},
 			wantOk: assert.False,
 		},
+		{
+			name: "with a stale value",
+			fields: fields{
+				counter: counter{
+					maxIteratedCount:  20,
+					minExpiredPercent: 0.25,
+
+					iteratedCount: 15,
+					expiredCount:  3,
+				},
+
+				storage: new(MockStorage),
+				clock:   clock,
+			},
+			args: args{
+				key: NewMockKeyWithID(23),
+				value: models.Value{
+					Data:           "data",
+					ExpirationTime: clock().Add(time.Second),
+					StaleTime:      clock().Add(-time.Second),
+				},
+			},
+			wantCounter: counter{
+				maxIteratedCount:  20,
+				minExpiredPercent: 0.25,
+
+				iteratedCount: 16,
+				expiredCount:  3,
+			},
+			wantOk: assert.True,
+		},
 		{
 			name: "with an expired value",
 			fields: fields{
@@ -138,6 +169,43 @@ func Test_iterator_handleIteration(test *testing.T) {
 			},
 			wantOk: assert.True,
 		},
+		{
+			name: "with a value expired on idleness",
+			fields: fields{
+				counter: counter{
+					maxIteratedCount:  20,
+					minExpiredPercent: 0.25,
+
+					iteratedCount: 15,
+					expiredCount:  3,
+				},
+
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Delete", NewMockKeyWithID(23))
+
+					return storage
+				}(),
+				clock: clock,
+			},
+			args: args{
+				key: NewMockKeyWithID(23),
+				value: models.Value{
+					Data:           "data",
+					ExpirationTime: clock().Add(time.Second),
+					IdleTimeout:    time.Second,
+					AccessTime:     models.NewAccessTime(clock().Add(-2 * time.Second)),
+				},
+			},
+			wantCounter: counter{
+				maxIteratedCount:  20,
+				minExpiredPercent: 0.25,
+
+				iteratedCount: 16,
+				expiredCount:  4,
+			},
+			wantOk: assert.True,
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			iterator := &iterator{
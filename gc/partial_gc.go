@@ -42,18 +42,35 @@ func NewPartialGC(
 // https://redis.io/commands/expire#how-redis-expires-keys
 //
 func (gc PartialGC) Clean(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			iterator :=
-				newIterator(gc.storage, gc.clock, gc.maxIteratedCount, gc.minExpiredPercent)
-			gc.storage.Iterate(hashmap.WithInterruption(ctx, iterator.handleIteration))
-
-			if iterator.stopClean() {
-				return
-			}
+	gc.CleanWithReport(ctx)
+}
+
+// CleanWithReport ...
+//
+// It's the same as the Clean() method, but additionally returns a report
+// summed up over all rounds.
+//
+func (gc PartialGC) CleanWithReport(ctx context.Context) Report {
+	startTime := gc.clock()
+	report := Report{StopReason: StopReasonContext}
+	for ctx.Err() == nil {
+		iterator :=
+			newIterator(gc.storage, gc.clock, gc.maxIteratedCount, gc.minExpiredPercent)
+		gc.storage.Iterate(hashmap.WithInterruption(ctx, iterator.handleIteration))
+
+		report.IteratedCount += iterator.iteratedCount
+		report.ExpiredCount += iterator.expiredCount
+		report.Rounds++
+
+		if ctx.Err() != nil {
+			break
+		}
+		if iterator.stopClean() {
+			report.StopReason = StopReasonMinExpiredPercent
+			break
 		}
 	}
+
+	report.Duration = gc.clock().Sub(startTime)
+	return report
 }
@@ -103,7 +103,7 @@ func TestNewPartialGC(test *testing.T) {
 	}
 }
 
-func TestPartialGC_Clean(test *testing.T) {
+func TestPartialGC_CleanWithReport(test *testing.T) {
 	type fields struct {
 		storage           hashmap.Storage
 		clock             models.Clock
@@ -118,6 +118,7 @@ func TestPartialGC_Clean(test *testing.T) {
 		name   string
 		fields fields
 		args   args
+		want   Report
 	}{
 		{
 			name: "without iterations",
@@ -140,6 +141,7 @@ func TestPartialGC_Clean(test *testing.T) {
 			args: args{
 				ctx: context.Background(),
 			},
+			want: Report{Rounds: 1, StopReason: StopReasonMinExpiredPercent},
 		},
 		{
 			name: "with a one try",
@@ -181,6 +183,12 @@ func TestPartialGC_Clean(test *testing.T) {
 			args: args{
 				ctx: context.Background(),
 			},
+			want: Report{
+				IteratedCount: 15,
+				ExpiredCount:  3,
+				Rounds:        1,
+				StopReason:    StopReasonMinExpiredPercent,
+			},
 		},
 		{
 			name: "with few tries",
@@ -226,6 +234,12 @@ func TestPartialGC_Clean(test *testing.T) {
 			args: args{
 				ctx: context.Background(),
 			},
+			want: Report{
+				IteratedCount: 30,
+				ExpiredCount:  5,
+				Rounds:        2,
+				StopReason:    StopReasonMinExpiredPercent,
+			},
 		},
 		{
 			name: "with canceled tries",
@@ -243,6 +257,36 @@ func TestPartialGC_Clean(test *testing.T) {
 					return ctx
 				}(),
 			},
+			want: Report{StopReason: StopReasonContext},
+		},
+		{
+			name: "with a duration",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.
+						On("Iterate", mock.MatchedBy(func(handler hashmap.Handler) bool {
+							return handler != nil
+						})).
+						Return(true).
+						Once()
+
+					return storage
+				}(),
+				clock: func() models.Clock {
+					var callCount int
+					return func() time.Time {
+						callCount++
+						return clock().Add(time.Duration(callCount) * time.Second)
+					}
+				}(),
+				maxIteratedCount:  20,
+				minExpiredPercent: 0.25,
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: Report{Rounds: 1, Duration: time.Second, StopReason: StopReasonMinExpiredPercent},
 		},
 		{
 			name: "with canceled iterations",
@@ -287,6 +331,12 @@ func TestPartialGC_Clean(test *testing.T) {
 					return ctx
 				}(),
 			},
+			want: Report{
+				IteratedCount: 1,
+				ExpiredCount:  1,
+				Rounds:        1,
+				StopReason:    StopReasonContext,
+			},
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
@@ -296,9 +346,10 @@ func TestPartialGC_Clean(test *testing.T) {
 				maxIteratedCount:  data.fields.maxIteratedCount,
 				minExpiredPercent: data.fields.minExpiredPercent,
 			}
-			gc.Clean(data.args.ctx)
+			got := gc.CleanWithReport(data.args.ctx)
 
 			mock.AssertExpectationsForObjects(test, data.fields.storage)
+			assert.Equal(test, data.want, got)
 		})
 	}
 }
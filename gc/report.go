@@ -0,0 +1,62 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StopReason ...
+type StopReason int
+
+// ...
+const (
+	// StopReasonCompleted means that a full scan of a storage was completed
+	StopReasonCompleted StopReason = iota
+	// StopReasonMinExpiredPercent means that a percent of expired values
+	// in the last round was less than the minimum one
+	StopReasonMinExpiredPercent
+	// StopReasonContext means that the context was done
+	StopReasonContext
+)
+
+// String ...
+func (reason StopReason) String() string {
+	switch reason {
+	case StopReasonCompleted:
+		return "completed"
+	case StopReasonMinExpiredPercent:
+		return "min expired percent"
+	case StopReasonContext:
+		return "context"
+	default:
+		return fmt.Sprintf("StopReason(%d)", int(reason))
+	}
+}
+
+// Report ...
+//
+// It describes a single cleaning.
+//
+type Report struct {
+	IteratedCount int
+	ExpiredCount  int
+	Rounds        int // rounds of iteration over a storage
+	Duration      time.Duration
+	StopReason    StopReason
+}
+
+//go:generate mockery -name=ReportingGC -inpkg -case=underscore -testonly
+
+// ReportingGC ...
+//
+// It's implemented by the PartialGC and TotalGC structures.
+//
+type ReportingGC interface {
+	GC
+
+	CleanWithReport(ctx context.Context) Report
+}
+
+// ReportHandler ...
+type ReportHandler func(report Report)
@@ -0,0 +1,42 @@
+package gc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopReason_String(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		reason StopReason
+		want   string
+	}{
+		{
+			name:   "completed",
+			reason: StopReasonCompleted,
+			want:   "completed",
+		},
+		{
+			name:   "min expired percent",
+			reason: StopReasonMinExpiredPercent,
+			want:   "min expired percent",
+		},
+		{
+			name:   "context",
+			reason: StopReasonContext,
+			want:   "context",
+		},
+		{
+			name:   "unknown",
+			reason: StopReason(23),
+			want:   "StopReason(23)",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.reason.String()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
@@ -13,14 +13,31 @@ type GC interface {
 }
 
 // Run ...
-func Run(ctx context.Context, gc GC, period time.Duration) {
+func Run(
+	ctx context.Context,
+	gc GC,
+	period time.Duration,
+	options ...RunOption,
+) {
+	var config runConfig
+	for _, option := range options {
+		option(&config)
+	}
+
+	reportingGC, isReporting := gc.(ReportingGC)
+	isReporting = isReporting && config.reportHandler != nil
+
 	ticker := time.NewTicker(period)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			gc.Clean(ctx)
+			if isReporting {
+				config.reportHandler(reportingGC.CleanWithReport(ctx))
+			} else {
+				gc.Clean(ctx)
+			}
 		case <-ctx.Done():
 			return
 		}
@@ -0,0 +1,21 @@
+package gc
+
+// RunOption ...
+type RunOption func(config *runConfig)
+
+type runConfig struct {
+	reportHandler ReportHandler
+}
+
+// RunWithReportHandler ...
+//
+// It's called after each cleaning. If garbage collection doesn't implement
+// the ReportingGC interface, the handler isn't called.
+//
+// It's called synchronously, so the next cleaning waits for it.
+//
+func RunWithReportHandler(reportHandler ReportHandler) RunOption {
+	return func(config *runConfig) {
+		config.reportHandler = reportHandler
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -31,3 +32,59 @@ func TestRun(test *testing.T) {
 
 	mock.AssertExpectationsForObjects(test, gc)
 }
+
+func TestRun_withReportHandler(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		gc          GC
+		wantReports assert.ValueAssertionFunc
+	}{
+		{
+			name: "with a reporting GC",
+			gc: func() GC {
+				gc := new(MockReportingGC)
+				gc.On("CleanWithReport", mock.Anything).Return(Report{IteratedCount: 23})
+
+				return gc
+			}(),
+			wantReports: assert.NotEmpty,
+		},
+		{
+			name: "with a not reporting GC",
+			gc: func() GC {
+				gc := new(MockGC)
+				gc.On("Clean", mock.Anything)
+
+				return gc
+			}(),
+			wantReports: assert.Empty,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var waiter sync.WaitGroup
+			waiter.Add(1)
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			var reports []Report
+			const period = 100 * time.Millisecond
+			go func() {
+				defer waiter.Done()
+
+				Run(ctx, data.gc, period, RunWithReportHandler(func(report Report) {
+					reports = append(reports, report)
+				}))
+			}()
+
+			time.Sleep(period * 2)
+			cancel()
+			waiter.Wait()
+
+			mock.AssertExpectationsForObjects(test, data.gc)
+			data.wantReports(test, reports)
+			for _, report := range reports {
+				assert.Equal(test, Report{IteratedCount: 23}, report)
+			}
+		})
+	}
+}
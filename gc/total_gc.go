@@ -31,13 +31,34 @@ func NewTotalGC(storage hashmap.Storage, options ...TotalGCOption) TotalGC {
 
 // Clean ...
 func (gc TotalGC) Clean(ctx context.Context) {
-	gc.storage.Iterate(hashmap.WithInterruption(ctx, gc.handleIteration))
+	gc.CleanWithReport(ctx)
 }
 
-func (gc TotalGC) handleIteration(key hashmap.Key, value interface{}) bool {
-	if value.(models.Value).IsExpired(gc.clock) {
-		gc.storage.Delete(key)
+// CleanWithReport ...
+//
+// It's the same as the Clean() method, but additionally returns a report.
+// There is always one round.
+//
+func (gc TotalGC) CleanWithReport(ctx context.Context) Report {
+	startTime := gc.clock()
+	report := Report{Rounds: 1, StopReason: StopReasonCompleted}
+	handler := hashmap.WithInterruption(ctx, gc.handleIteration(&report))
+	if !gc.storage.Iterate(handler) {
+		report.StopReason = StopReasonContext
 	}
 
-	return true
+	report.Duration = gc.clock().Sub(startTime)
+	return report
+}
+
+func (gc TotalGC) handleIteration(report *Report) hashmap.Handler {
+	return func(key hashmap.Key, value interface{}) bool {
+		if value.(models.Value).IsExpired(gc.clock) {
+			gc.storage.Delete(key)
+			report.ExpiredCount++
+		}
+
+		report.IteratedCount++
+		return true
+	}
 }
@@ -72,7 +72,7 @@ func TestNewTotalGC(test *testing.T) {
 	}
 }
 
-func TestTotalGC_Clean(test *testing.T) {
+func TestTotalGC_CleanWithReport(test *testing.T) {
 	type fields struct {
 		storage hashmap.Storage
 		clock   models.Clock
@@ -85,6 +85,7 @@ func TestTotalGC_Clean(test *testing.T) {
 		name   string
 		fields fields
 		args   args
+		want   Report
 	}{
 		{
 			name: "without iterations",
@@ -105,6 +106,7 @@ func TestTotalGC_Clean(test *testing.T) {
 			args: args{
 				ctx: context.Background(),
 			},
+			want: Report{Rounds: 1, StopReason: StopReasonCompleted},
 		},
 		{
 			name: "with iterations",
@@ -144,6 +146,39 @@ func TestTotalGC_Clean(test *testing.T) {
 			args: args{
 				ctx: context.Background(),
 			},
+			want: Report{
+				IteratedCount: 15,
+				ExpiredCount:  3,
+				Rounds:        1,
+				StopReason:    StopReasonCompleted,
+			},
+		},
+		{
+			name: "with a duration",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.
+						On("Iterate", mock.MatchedBy(func(handler hashmap.Handler) bool {
+							return handler != nil
+						})).
+						Return(true).
+						Once()
+
+					return storage
+				}(),
+				clock: func() models.Clock {
+					var callCount int
+					return func() time.Time {
+						callCount++
+						return clock().Add(time.Duration(callCount) * time.Second)
+					}
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+			},
+			want: Report{Rounds: 1, Duration: time.Second, StopReason: StopReasonCompleted},
 		},
 		{
 			name: "with canceled iterations",
@@ -186,13 +221,20 @@ func TestTotalGC_Clean(test *testing.T) {
 					return ctx
 				}(),
 			},
+			want: Report{
+				IteratedCount: 1,
+				ExpiredCount:  1,
+				Rounds:        1,
+				StopReason:    StopReasonContext,
+			},
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			gc := TotalGC{data.fields.storage, data.fields.clock}
-			gc.Clean(data.args.ctx)
+			got := gc.CleanWithReport(data.args.ctx)
 
 			mock.AssertExpectationsForObjects(test, data.fields.storage)
+			assert.Equal(test, data.want, got)
 		})
 	}
 }
@@ -208,10 +250,11 @@ func TestTotalGC_handleIteration(test *testing.T) {
 	}
 
 	for _, data := range []struct {
-		name   string
-		fields fields
-		args   args
-		want   assert.BoolAssertionFunc
+		name       string
+		fields     fields
+		args       args
+		wantReport Report
+		wantOk     assert.BoolAssertionFunc
 	}{
 		{
 			name: "with a not expired value",
@@ -223,7 +266,25 @@ func TestTotalGC_handleIteration(test *testing.T) {
 				key:   NewMockKeyWithID(23),
 				value: models.Value{Data: "data", ExpirationTime: clock().Add(time.Second)},
 			},
-			want: assert.True,
+			wantReport: Report{IteratedCount: 1},
+			wantOk:     assert.True,
+		},
+		{
+			name: "with a stale value",
+			fields: fields{
+				storage: new(MockStorage),
+				clock:   clock,
+			},
+			args: args{
+				key: NewMockKeyWithID(23),
+				value: models.Value{
+					Data:           "data",
+					ExpirationTime: clock().Add(time.Second),
+					StaleTime:      clock().Add(-time.Second),
+				},
+			},
+			wantReport: Report{IteratedCount: 1},
+			wantOk:     assert.True,
 		},
 		{
 			name: "with an expired value",
@@ -243,15 +304,57 @@ func TestTotalGC_handleIteration(test *testing.T) {
 					ExpirationTime: clock().Add(-time.Second),
 				},
 			},
-			want: assert.True,
+			wantReport: Report{IteratedCount: 1, ExpiredCount: 1},
+			wantOk:     assert.True,
+		},
+		{
+			name: "with a value not expired on idleness",
+			fields: fields{
+				storage: new(MockStorage),
+				clock:   clock,
+			},
+			args: args{
+				key: NewMockKeyWithID(23),
+				value: models.Value{
+					Data:        "data",
+					IdleTimeout: time.Second,
+					AccessTime:  models.NewAccessTime(clock()),
+				},
+			},
+			wantReport: Report{IteratedCount: 1},
+			wantOk:     assert.True,
+		},
+		{
+			name: "with a value expired on idleness",
+			fields: fields{
+				storage: func() hashmap.Storage {
+					storage := new(MockStorage)
+					storage.On("Delete", NewMockKeyWithID(23))
+
+					return storage
+				}(),
+				clock: clock,
+			},
+			args: args{
+				key: NewMockKeyWithID(23),
+				value: models.Value{
+					Data:        "data",
+					IdleTimeout: time.Second,
+					AccessTime:  models.NewAccessTime(clock().Add(-2 * time.Second)),
+				},
+			},
+			wantReport: Report{IteratedCount: 1, ExpiredCount: 1},
+			wantOk:     assert.True,
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			gc := TotalGC{data.fields.storage, data.fields.clock}
-			got := gc.handleIteration(data.args.key, data.args.value)
+			var gotReport Report
+			gotOk := gc.handleIteration(&gotReport)(data.args.key, data.args.value)
 
 			mock.AssertExpectationsForObjects(test, data.fields.storage, data.args.key)
-			data.want(test, got)
+			assert.Equal(test, data.wantReport, gotReport)
+			data.wantOk(test, gotOk)
 		})
 	}
 }
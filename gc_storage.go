@@ -0,0 +1,30 @@
+package cache
+
+import (
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// GCStorage ...
+//
+// It returns a view of the storage for an implementation of garbage
+// collection (see the gc package) run separately from the NewCacheWithGC()
+// function. The view deletes values via the cache, so the deletion is counted
+// in the size, the cost and statistics, notifies the removal listener
+// and subscribers and is atomic against other operations. Garbage collection
+// shouldn't be run on the storage itself.
+//
+func (cache Cache) GCStorage() hashmap.Storage {
+	return gcStorage{Storage: cache.storage, cache: cache}
+}
+
+// it's passed to an implementation of garbage collection, so that the latter
+// deletes values via the cache and only if their time to live still expired
+type gcStorage struct {
+	hashmap.Storage
+
+	cache Cache
+}
+
+func (storage gcStorage) Delete(key hashmap.Key) {
+	storage.cache.deleteExpired(key)
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/gc"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_GCStorage(test *testing.T) {
+	var gotReasons []RemovalReason
+	cache := NewCache(
+		WithClock(clock),
+		WithSyncRemovalListener(func(
+			key hashmap.Key,
+			data interface{},
+			reason RemovalReason,
+		) {
+			gotReasons = append(gotReasons, reason)
+		}),
+	)
+	cache.SetWithOptions(IntKey(1), "one", -time.Second, ValueWithTags("old"))
+	cache.Set(IntKey(2), "two", 0)
+
+	gcInstance := gc.NewTotalGC(cache.GCStorage(), gc.TotalGCWithClock(clock))
+	gcInstance.Clean(context.Background())
+
+	assert.Equal(test, 1, cache.Len())
+	assert.Equal(test, 1, cache.LiveLen())
+	assert.Equal(test, int64(1), cache.Stats().GCDeletes)
+	assert.Equal(test, []RemovalReason{RemovalReasonExpired}, gotReasons)
+	assert.Empty(test, cache.tags.keysByTag)
+}
+
+func Test_gcStorage_Delete(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		ttl      time.Duration
+		wantErr  error
+		wantSize int64
+	}{
+		{
+			name:     "with an expired value",
+			ttl:      -time.Second,
+			wantErr:  ErrKeyMissed,
+			wantSize: 0,
+		},
+		{
+			name:     "with a not expired value",
+			ttl:      time.Second,
+			wantErr:  nil,
+			wantSize: 1,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			cache.Set(IntKey(23), "data", data.ttl)
+
+			storage := gcStorage{Storage: cache.storage, cache: cache}
+			storage.Delete(IntKey(23))
+
+			_, gotErr := cache.Get(IntKey(23))
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(test, data.wantSize, cache.size.Load())
+		})
+	}
+}
+
+func Test_gcStorage_withEviction(test *testing.T) {
+	cache := NewCache(WithClock(clock), WithMaxSize(2))
+	cache.Set(IntKey(1), "one", -time.Second)
+	cache.Set(IntKey(2), "two", 0)
+
+	storage := gcStorage{Storage: cache.storage, cache: cache}
+	gc.NewTotalGC(storage, gc.TotalGCWithClock(clock)).Clean(context.Background())
+
+	// the collected value shouldn't be chosen for eviction
+	cache.Set(IntKey(3), "three", 0)
+
+	gotData, gotErr := cache.Get(IntKey(2))
+	assert.Equal(test, "two", gotData)
+	assert.NoError(test, gotErr)
+	assert.Equal(test, int64(2), cache.size.Load())
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/models"
+)
+
+type ttlJitter struct {
+	percent   float64
+	minJitter time.Duration
+	maxJitter time.Duration
+}
+
+func (jitter ttlJitter) isSet() bool {
+	return jitter.percent != 0 || jitter.minJitter != 0 || jitter.maxJitter != 0
+}
+
+// zero time to live means infinite one, and a negative one means already
+// expired, so both of them aren't jittered
+func (jitter ttlJitter) apply(
+	ttl time.Duration,
+	random models.Random,
+) time.Duration {
+	if ttl <= 0 || !jitter.isSet() {
+		return ttl
+	}
+
+	jitteredTTL := ttl
+	if jitter.percent != 0 {
+		factor := 1 + jitter.percent*(2*random()-1)
+		jitteredTTL = time.Duration(float64(ttl) * factor)
+	}
+	if jitter.minJitter != 0 || jitter.maxJitter != 0 {
+		jitterRange := float64(jitter.maxJitter - jitter.minJitter)
+		jitteredTTL += jitter.minJitter + time.Duration(random()*jitterRange)
+	}
+
+	// the jitter shouldn't make a value expired at once
+	if jitteredTTL <= 0 {
+		jitteredTTL = time.Nanosecond
+	}
+
+	return jitteredTTL
+}
+
+func (cache Cache) expirationTime(
+	currentTime time.Time,
+	ttl time.Duration,
+) time.Time {
+	if ttl == 0 {
+		return time.Time{}
+	}
+
+	return currentTime.Add(cache.ttlJitter.apply(ttl, cache.random))
+}
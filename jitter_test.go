@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/models"
+)
+
+func Test_ttlJitter_apply(test *testing.T) {
+	type args struct {
+		ttl    time.Duration
+		random models.Random
+	}
+
+	for _, data := range []struct {
+		name   string
+		jitter ttlJitter
+		args   args
+		want   time.Duration
+	}{
+		{
+			name:   "without a jitter",
+			jitter: ttlJitter{},
+			args: args{
+				ttl:    time.Minute,
+				random: func() float64 { return 0.75 },
+			},
+			want: time.Minute,
+		},
+		{
+			name:   "with zero time to live",
+			jitter: ttlJitter{percent: 0.1},
+			args: args{
+				ttl:    0,
+				random: func() float64 { return 0.75 },
+			},
+			want: 0,
+		},
+		{
+			name:   "with negative time to live",
+			jitter: ttlJitter{percent: 0.1},
+			args: args{
+				ttl:    -time.Minute,
+				random: func() float64 { return 0.75 },
+			},
+			want: -time.Minute,
+		},
+		{
+			name:   "with a percent/lower bound",
+			jitter: ttlJitter{percent: 0.1},
+			args: args{
+				ttl:    time.Minute,
+				random: func() float64 { return 0 },
+			},
+			want: 54 * time.Second,
+		},
+		{
+			name:   "with a percent/middle",
+			jitter: ttlJitter{percent: 0.1},
+			args: args{
+				ttl:    time.Minute,
+				random: func() float64 { return 0.75 },
+			},
+			want: 63 * time.Second,
+		},
+		{
+			name:   "with a range/lower bound",
+			jitter: ttlJitter{minJitter: -time.Second, maxJitter: 3 * time.Second},
+			args: args{
+				ttl:    time.Minute,
+				random: func() float64 { return 0 },
+			},
+			want: 59 * time.Second,
+		},
+		{
+			name:   "with a range/middle",
+			jitter: ttlJitter{minJitter: -time.Second, maxJitter: 3 * time.Second},
+			args: args{
+				ttl:    time.Minute,
+				random: func() float64 { return 0.75 },
+			},
+			want: 62 * time.Second,
+		},
+		{
+			name: "with a percent and a range",
+			jitter: ttlJitter{
+				percent:   0.1,
+				minJitter: -time.Second,
+				maxJitter: 3 * time.Second,
+			},
+			args: args{
+				ttl:    time.Minute,
+				random: func() float64 { return 0.75 },
+			},
+			want: 65 * time.Second,
+		},
+		{
+			name:   "with a limitation of the jittered time to live",
+			jitter: ttlJitter{minJitter: -time.Minute, maxJitter: -time.Minute},
+			args: args{
+				ttl:    time.Second,
+				random: func() float64 { return 0.75 },
+			},
+			want: time.Nanosecond,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.jitter.apply(data.args.ttl, data.args.random)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestCache_withTTLJitter(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		options []Option
+		update  func(cache Cache)
+		wantTTL time.Duration
+	}{
+		{
+			name:    "Set/without a jitter",
+			options: nil,
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Minute)
+			},
+			wantTTL: time.Minute,
+		},
+		{
+			name:    "Set/with a percent",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Minute)
+			},
+			wantTTL: 63 * time.Second,
+		},
+		{
+			name:    "Set/with a range",
+			options: []Option{WithTTLJitterRange(-time.Second, 3*time.Second)},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Minute)
+			},
+			wantTTL: 62 * time.Second,
+		},
+		{
+			name:    "Set/without time to live",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			wantTTL: NoExpiration,
+		},
+		{
+			name:    "SetMany",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.SetMany([]Entry{{Key: IntKey(23), Data: "data"}}, time.Minute)
+			},
+			wantTTL: 63 * time.Second,
+		},
+		{
+			name:    "Expire",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+				cache.Expire(IntKey(23), time.Minute) // nolint: errcheck
+			},
+			wantTTL: 63 * time.Second,
+		},
+		{
+			name:    "ExpireAt",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+				cache.ExpireAt(IntKey(23), clock().Add(time.Minute)) // nolint: errcheck
+			},
+			wantTTL: time.Minute,
+		},
+		{
+			name:    "IncrBy/with resetting of a time to live",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), 1, 0)
+				cache.IncrBy( // nolint: errcheck
+					IntKey(23),
+					1,
+					time.Minute,
+					CounterWithTTLReset(),
+				)
+			},
+			wantTTL: 63 * time.Second,
+		},
+		{
+			name:    "namespace",
+			options: []Option{WithTTLJitter(0.1)},
+			update: func(cache Cache) {
+				cache.Namespace("orders").Set(IntKey(23), "data", time.Minute)
+
+				// the jittered time to live of the namespace is jittered again
+				ttl, _ := cache.Namespace("orders").TTL(IntKey(23))
+				cache.Set(IntKey(23), "data", ttl)
+			},
+			wantTTL: 66150 * time.Millisecond,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			options := []Option{
+				WithClock(clock),
+				WithRandom(func() float64 { return 0.75 }),
+			}
+			cache := NewCache(append(options, data.options...)...)
+			data.update(cache)
+
+			gotTTL, err := cache.TTL(IntKey(23))
+
+			assert.Equal(test, data.wantTTL, gotTTL)
+			assert.NoError(test, err)
+		})
+	}
+}
+
+func TestNewCacheWithGC_withTTLJitter(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := NewCacheWithGC(
+		ctx,
+		WithGCAndClock(clock),
+		WithGCAndRandom(func() float64 { return 0 }),
+		WithGCAndTTLJitter(0.1),
+		WithGCAndTTLJitterRange(time.Second, time.Second),
+	)
+	cache.Set(IntKey(23), "data", time.Minute)
+
+	gotTTL, err := cache.TTL(IntKey(23))
+
+	assert.Equal(test, 55*time.Second, gotTTL)
+	assert.NoError(test, err)
+}
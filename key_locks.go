@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"sync"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+const keyLockCount = 256
+
+// it makes compound operations over a key atomic; keys with the same hash
+// share a lock
+type keyLocks struct {
+	locks [keyLockCount]sync.Mutex
+}
+
+func newKeyLocks() *keyLocks {
+	return new(keyLocks)
+}
+
+func (locks *keyLocks) lock(key hashmap.Key) (unlock func()) {
+	lock := &locks.locks[indexByHash(key.Hash())]
+	lock.Lock()
+
+	return lock.Unlock
+}
+
+// locks are acquired in ascending order of their indices to prevent deadlocks
+// between concurrent calls; each lock is acquired once regardless of a count
+// of its keys
+func (locks *keyLocks) lockMany(keyHashes []int) (unlock func()) {
+	var isUsed [keyLockCount]bool
+	for _, keyHash := range keyHashes {
+		isUsed[indexByHash(keyHash)] = true
+	}
+
+	usedLocks := make([]*sync.Mutex, 0, len(keyHashes))
+	for index := range isUsed {
+		if isUsed[index] {
+			lock := &locks.locks[index]
+			lock.Lock()
+
+			usedLocks = append(usedLocks, lock)
+		}
+	}
+
+	return func() {
+		for _, lock := range usedLocks {
+			lock.Unlock()
+		}
+	}
+}
+
+func (locks *keyLocks) lockAll() (unlock func()) {
+	for index := range locks.locks {
+		locks.locks[index].Lock()
+	}
+
+	return func() {
+		for index := range locks.locks {
+			locks.locks[index].Unlock()
+		}
+	}
+}
+
+func indexByHash(keyHash int) int {
+	return int(uint(keyHash) % keyLockCount)
+}
@@ -0,0 +1,315 @@
+package cache
+
+import (
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// it's a compound operation over a single key; the key stays locked
+// while the operation is running, so the latter is atomic both against
+// other operations and against garbage collection
+type keyTransaction struct {
+	cache Cache
+	key   hashmap.Key
+
+	data      interface{}
+	isPresent bool
+
+	// writing to the storage is deferred until the transaction is committed,
+	// so that batch transactions can write in bulk
+	isChanged        bool
+	isEvictionNeeded bool
+	removals         []removal
+	events           []Event
+
+	// deletion of a cleared or expired stored value isn't logged to the AOF,
+	// since the AOF replay skips such a value anyway
+	isDeletionUnlogged bool
+}
+
+func (cache Cache) runTransaction(
+	key hashmap.Key,
+	handler func(transaction *keyTransaction),
+) {
+	unlock := cache.locks.lock(key)
+	transaction := &keyTransaction{cache: cache, key: key}
+	transaction.data, transaction.isPresent = cache.storage.Get(key)
+	handler(transaction)
+	transaction.commit()
+	cache.logChanges([]keyTransaction{*transaction})
+	unlock()
+
+	cache.notifyOfRemovals(transaction.removals)
+	cache.events.publish(transaction.events)
+
+	// the eviction locks other keys, so it should be done
+	// after the transaction is finished
+	if transaction.isEvictionNeeded {
+		cache.evict()
+	}
+}
+
+// it's the same as the runTransaction() method, but for several keys at once;
+// equal keys share a transaction, so the handler sees changes made for
+// previous occurrences of a key
+func (cache Cache) runTransactions(
+	keys []hashmap.Key,
+	handler func(index int, transaction *keyTransaction),
+) {
+	if len(keys) == 0 {
+		return
+	}
+
+	uniqueKeys, uniqueKeyHashes, transactionIndices := groupEqualKeys(keys)
+	unlock := cache.locks.lockMany(uniqueKeyHashes)
+	data, oks := getMany(cache.storage, uniqueKeys)
+
+	transactions := make([]keyTransaction, len(uniqueKeys))
+	for index, key := range uniqueKeys {
+		transactions[index] = keyTransaction{
+			cache:     cache,
+			key:       key,
+			data:      data[index],
+			isPresent: oks[index],
+		}
+	}
+	for index, transactionIndex := range transactionIndices {
+		handler(index, &transactions[transactionIndex])
+	}
+
+	var isEvictionNeeded bool
+	var removals []removal
+	var events []Event
+	setKeys := make([]hashmap.Key, 0, len(transactions))
+	setData := make([]interface{}, 0, len(transactions))
+	var deletedKeys []hashmap.Key
+	for _, transaction := range transactions {
+		isEvictionNeeded = isEvictionNeeded || transaction.isEvictionNeeded
+		removals = append(removals, transaction.removals...)
+		events = append(events, transaction.events...)
+		if !transaction.isChanged {
+			continue
+		}
+
+		if transaction.isPresent {
+			setKeys = append(setKeys, transaction.key)
+			setData = append(setData, transaction.data)
+		} else {
+			deletedKeys = append(deletedKeys, transaction.key)
+		}
+	}
+	if len(setKeys) != 0 {
+		setMany(cache.storage, setKeys, setData)
+	}
+	if len(deletedKeys) != 0 {
+		deleteMany(cache.storage, deletedKeys)
+	}
+	cache.logChanges(transactions)
+	unlock()
+
+	cache.notifyOfRemovals(removals)
+	cache.events.publish(events)
+
+	if isEvictionNeeded {
+		cache.evict()
+	}
+}
+
+func (transaction *keyTransaction) commit() {
+	if !transaction.isChanged {
+		return
+	}
+
+	if transaction.isPresent {
+		transaction.cache.storage.Set(transaction.key, transaction.data)
+	} else {
+		transaction.cache.storage.Delete(transaction.key)
+	}
+}
+
+// it returns a present and not expired value, excluding a negative one
+func (transaction *keyTransaction) value() (models.Value, error) {
+	value, err := transaction.entry()
+	if err == nil && value.Err != nil {
+		return models.Value{}, value.Err
+	}
+
+	return value, err
+}
+
+// it's the same as the value() method, but including a negative value
+func (transaction *keyTransaction) entry() (models.Value, error) {
+	if !transaction.isPresent ||
+		transaction.cache.isCleared(transaction.data.(models.Value)) {
+		return models.Value{}, ErrKeyMissed
+	}
+
+	value := transaction.data.(models.Value)
+	if value.IsExpired(transaction.cache.clock) {
+		return models.Value{}, ErrKeyExpired
+	}
+
+	return value, nil
+}
+
+// it doesn't notify the eviction policy and doesn't change the cost,
+// so it's intended for changing metadata of a present value only
+func (transaction *keyTransaction) modify(value models.Value) {
+	transaction.data = value
+	transaction.isChanged = true
+}
+
+func (transaction *keyTransaction) set(value models.Value) (ok bool) {
+	cache := transaction.cache
+	if cache.maxCost > 0 && value.Cost > cache.maxCost {
+		// the previous data of the key shouldn't stay instead of the new one
+		transaction.delete(RemovalReasonReplaced)
+		return false
+	}
+
+	if !transaction.isPresent && !cache.admit(transaction.key, value.Cost) {
+		return false
+	}
+
+	value.Generation = cache.generation.Load()
+
+	costDelta := value.Cost
+	if transaction.isPresent {
+		costDelta -= transaction.data.(models.Value).Cost
+	} else {
+		cache.size.Add(1)
+	}
+	cache.cost.Add(costDelta)
+
+	if cache.evictionPolicy != nil {
+		if transaction.isPresent {
+			cache.evictionPolicy.OnAccess(transaction.key)
+		} else {
+			cache.evictionPolicy.OnInsert(transaction.key)
+		}
+	}
+
+	if transaction.isPresent {
+		transaction.addRemoval(RemovalReasonReplaced)
+		cache.tags.remove(transaction.key, transaction.data.(models.Value).Tags)
+	}
+	cache.tags.add(transaction.key, value.Tags)
+	transaction.addEvent(EventTypeSet, value.Data)
+	if !transaction.isPresent || costDelta > 0 {
+		transaction.isEvictionNeeded = true
+	}
+	transaction.data, transaction.isPresent = value, true
+	transaction.isChanged = true
+	cache.stats.sets.add(1)
+
+	return true
+}
+
+// the eviction policy is notified even if the key is missed,
+// so that it doesn't keep keys deleted past the cache
+func (transaction *keyTransaction) delete(reason RemovalReason) (ok bool) {
+	cache := transaction.cache
+	if transaction.isPresent {
+		if cache.aof != nil && !transaction.isChanged {
+			value := transaction.data.(models.Value)
+			transaction.isDeletionUnlogged =
+				cache.isCleared(value) || value.IsExpired(cache.clock)
+		}
+
+		transaction.addRemoval(reason)
+		cache.stats.addRemoval(reason)
+
+		cache.tags.remove(transaction.key, transaction.data.(models.Value).Tags)
+
+		cache.size.Add(-1)
+		cache.cost.Add(-transaction.data.(models.Value).Cost)
+	}
+	if cache.evictionPolicy != nil {
+		cache.evictionPolicy.OnDelete(transaction.key)
+	}
+
+	ok = transaction.isPresent
+	transaction.data, transaction.isPresent = nil, false
+	transaction.isChanged = transaction.isChanged || ok
+
+	return ok
+}
+
+// it should be called before the present value is changed
+func (transaction *keyTransaction) addRemoval(reason RemovalReason) {
+	cache := transaction.cache
+	if cache.removalListener == nil && !cache.events.hasSubscriptions() {
+		return
+	}
+
+	value := transaction.data.(models.Value)
+	switch {
+	case cache.isCleared(value):
+		reason = RemovalReasonCleared
+	case value.IsExpired(cache.clock):
+		reason = RemovalReasonExpired
+	}
+
+	if cache.removalListener != nil {
+		transaction.removals = append(transaction.removals, removal{
+			key:    transaction.key,
+			data:   value.Data,
+			reason: reason,
+		})
+	}
+	if eventType, ok := eventTypeByRemovalReason(reason); ok {
+		transaction.addEvent(eventType, value.Data)
+	}
+}
+
+func (transaction *keyTransaction) addEvent(eventType EventType, data interface{}) {
+	cache := transaction.cache
+	if !cache.events.hasSubscriptions() {
+		return
+	}
+
+	transaction.events = append(transaction.events, Event{
+		Type: eventType,
+		Key:  transaction.key,
+		Data: data,
+		Time: cache.clock(),
+	})
+}
+
+func groupEqualKeys(keys []hashmap.Key) (
+	uniqueKeys []hashmap.Key,
+	uniqueKeyHashes []int,
+	uniqueKeyIndices []int,
+) {
+	uniqueKeys = make([]hashmap.Key, 0, len(keys))
+	uniqueKeyHashes = make([]int, 0, len(keys))
+	uniqueKeyIndices = make([]int, len(keys))
+	uniqueKeyIndicesByHash := make(map[int]int, len(keys))
+	for index, key := range keys {
+		hash := key.Hash()
+
+		uniqueKeyIndex, ok := uniqueKeyIndicesByHash[hash]
+		if ok && !uniqueKeys[uniqueKeyIndex].Equals(key) {
+			// keys with colliding hashes are rare, so a linear search is enough
+			uniqueKeyIndex, ok = -1, false
+			for otherIndex, otherKey := range uniqueKeys {
+				if uniqueKeyHashes[otherIndex] == hash && otherKey.Equals(key) {
+					uniqueKeyIndex, ok = otherIndex, true
+					break
+				}
+			}
+		}
+		if !ok {
+			uniqueKeyIndex = len(uniqueKeys)
+			uniqueKeys = append(uniqueKeys, key)
+			uniqueKeyHashes = append(uniqueKeyHashes, hash)
+			if _, isHashUsed := uniqueKeyIndicesByHash[hash]; !isHashUsed {
+				uniqueKeyIndicesByHash[hash] = uniqueKeyIndex
+			}
+		}
+
+		uniqueKeyIndices[index] = uniqueKeyIndex
+	}
+
+	return uniqueKeys, uniqueKeyHashes, uniqueKeyIndices
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// ErrLoaderPanicked ...
+//
+// The LoaderPanicError type wraps it, so it can be checked
+// via the errors.Is() function.
+//
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+// LoaderPanicError ...
+//
+// It's returned by the Cache.GetOrLoad() method to all callers sharing
+// a loader call, when the loader panics. It holds the panic value
+// and the stack of the loader goroutine.
+//
+type LoaderPanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error ...
+func (err LoaderPanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrLoaderPanicked, err.Value)
+}
+
+// Unwrap ...
+func (err LoaderPanicError) Unwrap() error {
+	return ErrLoaderPanicked
+}
+
+type loadFunc func(ctx context.Context) (data interface{}, err error)
+
+type loadCall struct {
+	key         hashmap.Key
+	done        chan struct{}
+	cancel      context.CancelFunc
+	waiterCount int
+
+	data interface{}
+	err  error
+}
+
+// it's indexed by key hashes, because the hashmap.Key interface
+// doesn't guarantee that its implementations are comparable
+type loadGroup struct {
+	lock  sync.Mutex
+	calls map[int][]*loadCall
+}
+
+func newLoadGroup() *loadGroup {
+	return &loadGroup{calls: make(map[int][]*loadCall)}
+}
+
+// the load is shared by all callers with the same key; it isn't canceled
+// when one of them leaves, only when all of them do
+func (group *loadGroup) do(
+	ctx context.Context,
+	key hashmap.Key,
+	load loadFunc,
+) (data interface{}, err error) {
+	group.lock.Lock()
+	call, ok := group.findCall(key)
+	if !ok {
+		loadCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &loadCall{key: key, done: make(chan struct{}), cancel: cancel}
+		group.addCall(call)
+
+		go group.runCall(loadCtx, call, load)
+	}
+	call.waiterCount++
+	group.lock.Unlock()
+
+	select {
+	case <-call.done:
+		return call.data, call.err
+	case <-ctx.Done():
+		group.lock.Lock()
+		call.waiterCount--
+		if call.waiterCount == 0 {
+			group.deleteCall(call)
+			call.cancel()
+		}
+		group.lock.Unlock()
+
+		return nil, ctx.Err()
+	}
+}
+
+func (group *loadGroup) runCall(
+	ctx context.Context,
+	call *loadCall,
+	load loadFunc,
+) {
+	defer call.cancel()
+	// the load runs in a separate goroutine, so its panic can't be recovered
+	// by the callers and should be passed to them instead
+	defer func() {
+		if value := recover(); value != nil {
+			call.data = nil
+			call.err = LoaderPanicError{Value: value, Stack: debug.Stack()}
+		}
+
+		group.lock.Lock()
+		group.deleteCall(call)
+		group.lock.Unlock()
+
+		close(call.done)
+	}()
+
+	call.data, call.err = load(ctx)
+}
+
+func (group *loadGroup) findCall(key hashmap.Key) (*loadCall, bool) {
+	for _, call := range group.calls[key.Hash()] {
+		if call.key.Equals(key) {
+			return call, true
+		}
+	}
+
+	return nil, false
+}
+
+func (group *loadGroup) addCall(call *loadCall) {
+	hash := call.key.Hash()
+	group.calls[hash] = append(group.calls[hash], call)
+}
+
+func (group *loadGroup) deleteCall(call *loadCall) {
+	hash := call.key.Hash()
+	calls := group.calls[hash]
+	for index, otherCall := range calls {
+		if otherCall != call {
+			continue
+		}
+
+		calls = append(calls[:index:index], calls[index+1:]...)
+		if len(calls) != 0 {
+			group.calls[hash] = calls
+		} else {
+			delete(group.calls, hash)
+		}
+
+		return
+	}
+}
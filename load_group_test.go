@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func Test_loadGroup_do(test *testing.T) {
+	keyOne := NewMockKeyWithID(12)
+	keyOne.On("Hash").Return(23)
+	keyOne.
+		On("Equals", mock.MatchedBy(func(key hashmap.Key) bool { return true })).
+		Return(false)
+
+	keyTwo := NewMockKeyWithID(42)
+	keyTwo.On("Hash").Return(23)
+
+	group := newLoadGroup()
+	gotDataOne, gotErrOne := group.do(
+		context.Background(),
+		keyOne,
+		func(ctx context.Context) (interface{}, error) {
+			// keys with the same hash shouldn't share a load
+			data, err := group.do(
+				context.Background(),
+				keyTwo,
+				func(ctx context.Context) (interface{}, error) {
+					return "two", nil
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return "one and " + data.(string), nil
+		},
+	)
+
+	mock.AssertExpectationsForObjects(test, keyOne, keyTwo)
+	assert.Equal(test, "one and two", gotDataOne)
+	assert.NoError(test, gotErrOne)
+	assert.Empty(test, group.calls)
+}
+
+func Test_loadGroup_do_withPanic(test *testing.T) {
+	group := newLoadGroup()
+	gotData, gotErr := group.do(
+		context.Background(),
+		IntKey(23),
+		func(ctx context.Context) (interface{}, error) {
+			panic("test")
+		},
+	)
+
+	assert.Nil(test, gotData)
+	assert.True(test, errors.Is(gotErr, ErrLoaderPanicked))
+	assert.EqualError(test, gotErr, "loader panicked: test")
+	if assert.IsType(test, LoaderPanicError{}, gotErr) {
+		assert.Equal(test, "test", gotErr.(LoaderPanicError).Value)
+		assert.NotEmpty(test, gotErr.(LoaderPanicError).Stack)
+	}
+	assert.Empty(test, group.calls)
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type label struct {
+	name  string
+	value string
+}
+
+func withLabel(labels []label, name string, value string) []label {
+	extendedLabels := make([]label, len(labels), len(labels)+1)
+	copy(extendedLabels, labels)
+
+	return append(extendedLabels, label{name: name, value: value})
+}
+
+type sample struct {
+	labels []label
+	value  float64
+}
+
+type family struct {
+	name       string
+	help       string
+	metricType string
+	samples    []sample
+}
+
+func newGaugeFamily(name string, help string) *family {
+	return &family{name: name, help: help, metricType: "gauge"}
+}
+
+func newCounterFamily(name string, help string) *family {
+	return &family{name: name, help: help, metricType: "counter"}
+}
+
+func (family *family) add(labels []label, value float64) {
+	family.samples = append(family.samples, sample{labels: labels, value: value})
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (writer *countingWriter) Write(data []byte) (int, error) {
+	count, err := writer.writer.Write(data)
+	writer.count += int64(count)
+
+	return count, err
+}
+
+// it remembers the first error and skips writing after it
+type expositionWriter struct {
+	writer io.Writer
+	err    error
+}
+
+func (writer *expositionWriter) writeFamily(family *family) {
+	// families without samples are omitted, like in the Prometheus client
+	if len(family.samples) == 0 {
+		return
+	}
+
+	writer.writeString("# HELP " + family.name + " " + family.help + "\n")
+	writer.writeString("# TYPE " + family.name + " " + family.metricType + "\n")
+	for _, sample := range family.samples {
+		writer.writeString(family.name)
+		if len(sample.labels) != 0 {
+			writer.writeString("{")
+			for index, label := range sample.labels {
+				if index != 0 {
+					writer.writeString(",")
+				}
+
+				value := labelValueReplacer.Replace(label.value)
+				writer.writeString(label.name + `="` + value + `"`)
+			}
+			writer.writeString("}")
+		}
+
+		writer.writeString(" " + formatValue(sample.value) + "\n")
+	}
+}
+
+func (writer *expositionWriter) writeString(text string) {
+	if writer.err != nil {
+		return
+	}
+
+	_, writer.err = io.WriteString(writer.writer, text)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, +1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
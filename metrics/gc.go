@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+)
+
+type gcKey struct {
+	cacheName string
+	gcName    string
+}
+
+type gcCounters struct {
+	runs     atomic.Int64
+	duration atomic.Int64 // in nanoseconds
+}
+
+type instrumentedGC struct {
+	gc       gc.GC
+	clock    models.Clock
+	counters *gcCounters
+}
+
+func (gc instrumentedGC) Clean(ctx context.Context) {
+	gc.measure(func() { gc.gc.Clean(ctx) })
+}
+
+func (gc instrumentedGC) measure(clean func()) {
+	startTime := gc.clock()
+	clean()
+
+	gc.counters.runs.Add(1)
+	gc.counters.duration.Add(int64(gc.clock().Sub(startTime)))
+}
+
+// it keeps the gc.ReportingGC interface of the wrapped garbage collection,
+// so that the gc.Run() function still can forward its reports
+type instrumentedReportingGC struct {
+	instrumentedGC
+
+	reportingGC gc.ReportingGC
+}
+
+func (gc instrumentedReportingGC) CleanWithReport(
+	ctx context.Context,
+) (report gc.Report) {
+	gc.measure(func() { report = gc.reportingGC.CleanWithReport(ctx) })
+	return report
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"github.com/thewizardplusplus/go-cache/gc"
+)
+
+//go:generate mockery -name=GC -inpkg -case=underscore -testonly
+
+// GC ...
+//
+// It's used only for mock generating.
+//
+type GC interface {
+	gc.GC
+}
+
+//go:generate mockery -name=ReportingGC -inpkg -case=underscore -testonly
+
+// ReportingGC ...
+//
+// It's used only for mock generating.
+//
+type ReportingGC interface {
+	gc.ReportingGC
+}
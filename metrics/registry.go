@@ -0,0 +1,275 @@
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	cache "github.com/thewizardplusplus/go-cache"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// ...
+var (
+	ErrEmptyCacheName         = errors.New("empty cache name")
+	ErrCacheAlreadyRegistered = errors.New("cache already registered")
+)
+
+// ContentType ...
+//
+// It's a content type of the Prometheus text exposition format.
+//
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Cache ...
+//
+// It's implemented by the cache.Cache structure; for the typed.Cache
+// structure, use its Untyped() method.
+//
+type Cache interface {
+	Len() int
+	Cost() int64
+	Stats() cache.Stats
+}
+
+// Registry ...
+//
+// It collects metrics of named caches and of their garbage collection
+// and exposes them in the Prometheus text exposition format.
+// It implements the http.Handler interface.
+//
+type Registry struct {
+	clock models.Clock
+
+	lock     sync.RWMutex
+	caches   map[string]Cache
+	gcCounts map[gcKey]*gcCounters
+}
+
+// NewRegistry ...
+func NewRegistry(options ...RegistryOption) *Registry {
+	registry := &Registry{
+		caches:   make(map[string]Cache),
+		gcCounts: make(map[gcKey]*gcCounters),
+
+		// default options
+		clock: time.Now,
+	}
+	for _, option := range options {
+		option(registry)
+	}
+
+	return registry
+}
+
+// Register ...
+//
+// The name is exposed as the "cache" label, so it should be unique
+// within the registry.
+//
+func (registry *Registry) Register(name string, cache Cache) error {
+	if name == "" {
+		return ErrEmptyCacheName
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	if _, ok := registry.caches[name]; ok {
+		return fmt.Errorf("%w: %q", ErrCacheAlreadyRegistered, name)
+	}
+
+	registry.caches[name] = cache
+	return nil
+}
+
+// Unregister ...
+//
+// It also drops metrics of garbage collection of the cache.
+//
+func (registry *Registry) Unregister(name string) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	delete(registry.caches, name)
+	for key := range registry.gcCounts {
+		if key.cacheName == name {
+			delete(registry.gcCounts, key)
+		}
+	}
+}
+
+// InstrumentGC ...
+//
+// It wraps the garbage collection, so that the registry counts its runs
+// and their duration for the named cache. An implementation
+// of the garbage collection is exposed as the "gc" label.
+//
+// If the garbage collection implements the gc.ReportingGC interface,
+// the result implements it too.
+//
+// The cache may be registered after the instrumentation.
+//
+func (registry *Registry) InstrumentGC(cacheName string, gcInstance gc.GC) gc.GC {
+	key := gcKey{cacheName: cacheName, gcName: fmt.Sprintf("%T", gcInstance)}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	counters, ok := registry.gcCounts[key]
+	if !ok {
+		counters = new(gcCounters)
+		registry.gcCounts[key] = counters
+	}
+
+	instrumentedGCInstance :=
+		instrumentedGC{gc: gcInstance, clock: registry.clock, counters: counters}
+	if reportingGC, ok := gcInstance.(gc.ReportingGC); ok {
+		return instrumentedReportingGC{
+			instrumentedGC: instrumentedGCInstance,
+			reportingGC:    reportingGC,
+		}
+	}
+
+	return instrumentedGCInstance
+}
+
+// InstrumentGCFactory ...
+//
+// It's the same as the InstrumentGC() method, but for a factory intended
+// for the cache.WithGCAndGCFactory() option.
+//
+func (registry *Registry) InstrumentGCFactory(
+	cacheName string,
+	gcFactory cache.GCFactory,
+) cache.GCFactory {
+	return func(storage hashmap.Storage, clock models.Clock) gc.GC {
+		return registry.InstrumentGC(cacheName, gcFactory(storage, clock))
+	}
+}
+
+// WriteTo ...
+//
+// It writes the metrics in the Prometheus text exposition format. Samples
+// are sorted by their labels, so the output is deterministic.
+//
+func (registry *Registry) WriteTo(writer io.Writer) (int64, error) {
+	bufferedWriter := bufio.NewWriter(writer)
+	countingWriter := &countingWriter{writer: bufferedWriter}
+	exposition := &expositionWriter{writer: countingWriter}
+	for _, family := range registry.collect() {
+		exposition.writeFamily(family)
+	}
+	if exposition.err != nil {
+		return countingWriter.count, exposition.err
+	}
+
+	err := bufferedWriter.Flush()
+	return countingWriter.count, err
+}
+
+// ServeHTTP ...
+func (registry *Registry) ServeHTTP(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+	writer.Header().Set("Content-Type", ContentType)
+	registry.WriteTo(writer) // nolint: errcheck
+}
+
+func (registry *Registry) collect() []*family {
+	registry.lock.RLock()
+	cacheNames := make([]string, 0, len(registry.caches))
+	caches := make(map[string]Cache, len(registry.caches))
+	for name, cache := range registry.caches {
+		cacheNames = append(cacheNames, name)
+		caches[name] = cache
+	}
+
+	gcKeys := make([]gcKey, 0, len(registry.gcCounts))
+	gcCounts := make(map[gcKey]*gcCounters, len(registry.gcCounts))
+	for key, counters := range registry.gcCounts {
+		gcKeys = append(gcKeys, key)
+		gcCounts[key] = counters
+	}
+	registry.lock.RUnlock()
+
+	sort.Strings(cacheNames)
+	sort.Slice(gcKeys, func(i int, j int) bool {
+		if gcKeys[i].cacheName != gcKeys[j].cacheName {
+			return gcKeys[i].cacheName < gcKeys[j].cacheName
+		}
+
+		return gcKeys[i].gcName < gcKeys[j].gcName
+	})
+
+	entries := newGaugeFamily("go_cache_entries",
+		"Count of values, including expired but not yet deleted ones.")
+	cost := newGaugeFamily("go_cache_cost", "Total cost of values.")
+	hits := newCounterFamily("go_cache_hits_total",
+		"Count of getting hits by a kind of a value.")
+	misses := newCounterFamily("go_cache_misses_total",
+		"Count of getting misses by a reason.")
+	sets := newCounterFamily("go_cache_sets_total", "Count of settings.")
+	deletes := newCounterFamily("go_cache_deletes_total",
+		"Count of explicit deletions of present values.")
+	gcDeletes := newCounterFamily("go_cache_gc_deletes_total",
+		"Count of deletions of expired values by garbage collection.")
+	evictions := newCounterFamily("go_cache_evictions_total",
+		"Count of evictions.")
+	for _, name := range cacheNames {
+		cache := caches[name]
+		stats := cache.Stats()
+		labels := []label{{name: "cache", value: name}}
+
+		entries.add(labels, float64(cache.Len()))
+		cost.add(labels, float64(cache.Cost()))
+		hits.add(withLabel(labels, "kind", "regular"), float64(stats.Hits))
+		hits.add(withLabel(labels, "kind", "negative"), float64(stats.NegativeHits))
+		hits.add(withLabel(labels, "kind", "stale"), float64(stats.StaleHits))
+		misses.add(withLabel(labels, "reason", "missed"), float64(stats.Misses))
+		misses.add(
+			withLabel(labels, "reason", "expired"),
+			float64(stats.ExpiredMisses),
+		)
+		sets.add(labels, float64(stats.Sets))
+		deletes.add(labels, float64(stats.Deletes))
+		gcDeletes.add(labels, float64(stats.GCDeletes))
+		evictions.add(labels, float64(stats.Evictions))
+	}
+
+	gcRuns := newCounterFamily("go_cache_gc_runs_total",
+		"Count of runs of garbage collection.")
+	gcDuration := newCounterFamily("go_cache_gc_duration_seconds_total",
+		"Total duration of runs of garbage collection.")
+	for _, key := range gcKeys {
+		counters := gcCounts[key]
+		labels := []label{
+			{name: "cache", value: key.cacheName},
+			{name: "gc", value: key.gcName},
+		}
+
+		gcRuns.add(labels, float64(counters.runs.Load()))
+		gcDuration.add(labels, time.Duration(counters.duration.Load()).Seconds())
+	}
+
+	return []*family{
+		entries,
+		cost,
+		hits,
+		misses,
+		sets,
+		deletes,
+		gcDeletes,
+		evictions,
+		gcRuns,
+		gcDuration,
+	}
+}
@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"github.com/thewizardplusplus/go-cache/models"
+)
+
+// RegistryOption ...
+type RegistryOption func(registry *Registry)
+
+// RegistryWithClock ...
+//
+// It's used for measuring of a duration of garbage collection.
+//
+// Default: the time.Now() function.
+//
+func RegistryWithClock(clock models.Clock) RegistryOption {
+	return func(registry *Registry) {
+		registry.clock = clock
+	}
+}
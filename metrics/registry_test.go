@@ -0,0 +1,332 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	cache "github.com/thewizardplusplus/go-cache"
+	"github.com/thewizardplusplus/go-cache/eviction"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+type IntKey int
+
+func (key IntKey) Hash() int {
+	return int(key)
+}
+
+func (key IntKey) Equals(other hashmap.Key) bool {
+	return key == other.(IntKey)
+}
+
+func TestRegistry_Register(test *testing.T) {
+	type args struct {
+		name string
+	}
+
+	for _, data := range []struct {
+		name           string
+		registeredName string
+		args           args
+		wantErr        error
+	}{
+		{
+			name:           "success",
+			registeredName: "users",
+			args:           args{name: "sessions"},
+			wantErr:        nil,
+		},
+		{
+			name:           "error with an empty name",
+			registeredName: "users",
+			args:           args{name: ""},
+			wantErr:        ErrEmptyCacheName,
+		},
+		{
+			name:           "error with a registered name",
+			registeredName: "users",
+			args:           args{name: "users"},
+			wantErr:        ErrCacheAlreadyRegistered,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			registry := NewRegistry()
+			err := registry.Register(data.registeredName, cache.NewCache())
+			require.NoError(test, err)
+
+			gotErr := registry.Register(data.args.name, cache.NewCache())
+
+			assert.True(test, errors.Is(gotErr, data.wantErr))
+		})
+	}
+}
+
+func TestRegistry_Unregister(test *testing.T) {
+	registry := NewRegistry()
+	err := registry.Register("users", cache.NewCache())
+	require.NoError(test, err)
+
+	gcInstance := new(MockGC)
+	gcInstance.On("Clean", context.Background())
+
+	registry.InstrumentGC("users", gcInstance).Clean(context.Background())
+	registry.Unregister("users")
+
+	var buffer bytes.Buffer
+	_, err = registry.WriteTo(&buffer)
+
+	mock.AssertExpectationsForObjects(test, gcInstance)
+	assert.NoError(test, err)
+	assert.Empty(test, buffer.String())
+
+	err = registry.Register("users", cache.NewCache())
+	assert.NoError(test, err)
+}
+
+func TestRegistry_InstrumentGC(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		gc            gc.GC
+		wantReporting assert.BoolAssertionFunc
+	}{
+		{
+			name: "with a not reporting GC",
+			gc: func() gc.GC {
+				gcInstance := new(MockGC)
+				gcInstance.On("Clean", context.Background())
+
+				return gcInstance
+			}(),
+			wantReporting: assert.False,
+		},
+		{
+			name: "with a reporting GC",
+			gc: func() gc.GC {
+				gcInstance := new(MockReportingGC)
+				gcInstance.
+					On("CleanWithReport", context.Background()).
+					Return(gc.Report{IteratedCount: 23})
+
+				return gcInstance
+			}(),
+			wantReporting: assert.True,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			registry := NewRegistry(RegistryWithClock(newSteppingClock()))
+			got := registry.InstrumentGC("users", data.gc)
+
+			reportingGC, isReporting := got.(gc.ReportingGC)
+			if isReporting {
+				report := reportingGC.CleanWithReport(context.Background())
+				assert.Equal(test, gc.Report{IteratedCount: 23}, report)
+			} else {
+				got.Clean(context.Background())
+			}
+
+			mock.AssertExpectationsForObjects(test, data.gc)
+			data.wantReporting(test, isReporting)
+
+			counters := registry.gcCounts[gcKey{
+				cacheName: "users",
+				gcName:    fmt.Sprintf("%T", data.gc),
+			}]
+			if assert.NotNil(test, counters) {
+				assert.Equal(test, int64(1), counters.runs.Load())
+				assert.Equal(test, int64(time.Second/4), counters.duration.Load())
+			}
+		})
+	}
+}
+
+func TestRegistry_InstrumentGCFactory(test *testing.T) {
+	gcInstance := new(MockGC)
+	gcInstance.On("Clean", context.Background())
+
+	var gotStorage hashmap.Storage
+	registry := NewRegistry()
+	gcFactory := registry.InstrumentGCFactory(
+		"users",
+		func(storage hashmap.Storage, clock models.Clock) gc.GC {
+			gotStorage = storage
+			return gcInstance
+		},
+	)
+
+	storage := hashmap.NewConcurrentHashMap()
+	gcFactory(storage, time.Now).Clean(context.Background())
+
+	mock.AssertExpectationsForObjects(test, gcInstance)
+	assert.Equal(test, storage, gotStorage)
+
+	counters := registry.gcCounts[gcKey{cacheName: "users", gcName: "*metrics.MockGC"}]
+	if assert.NotNil(test, counters) {
+		assert.Equal(test, int64(1), counters.runs.Load())
+	}
+}
+
+func TestRegistry_WriteTo(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		prepare func(test *testing.T, registry *Registry)
+	}{
+		{
+			name:    "empty",
+			prepare: func(test *testing.T, registry *Registry) {},
+		},
+		{
+			name: "single_cache",
+			prepare: func(test *testing.T, registry *Registry) {
+				users := cache.NewCache(cache.WithClock(clock))
+				users.SetWithCost(IntKey(1), "one", 0, 10)
+				users.SetWithCost(IntKey(2), "two", 0, 5)
+				users.Set(IntKey(3), "three", -time.Second)
+				users.Get(IntKey(1)) // nolint: errcheck
+				users.Get(IntKey(2)) // nolint: errcheck
+				users.Get(IntKey(3)) // nolint: errcheck
+				users.Get(IntKey(4)) // nolint: errcheck
+				users.SetNegative(IntKey(5), 0)
+				users.Get(IntKey(5)) // nolint: errcheck
+				users.SetWithOptions(
+					IntKey(6),
+					"six",
+					-time.Second,
+					cache.ValueWithStaleTTL(time.Minute),
+				)
+				users.Get(IntKey(6)) // nolint: errcheck
+				users.Delete(IntKey(2))
+
+				err := registry.Register("users", users)
+				require.NoError(test, err)
+			},
+		},
+		{
+			name: "several_caches_with_gc",
+			prepare: func(test *testing.T, registry *Registry) {
+				users := cache.NewCache(
+					cache.WithClock(clock),
+					cache.WithMaxSize(1),
+					cache.WithEvictionPolicy(eviction.NewLRU()),
+				)
+				users.Set(IntKey(1), "one", 0)
+				users.Set(IntKey(2), "two", 0)
+				users.Get(IntKey(1)) // nolint: errcheck
+
+				sessions := cache.NewCache(cache.WithClock(clock))
+				sessions.Set(IntKey(1), "one", 0)
+				sessions.Get(IntKey(1)) // nolint: errcheck
+
+				for name, instance := range map[string]cache.Cache{
+					"users":                    users,
+					"sessions":                 sessions,
+					"with \"special\"\\\nname": cache.NewCache(),
+				} {
+					err := registry.Register(name, instance)
+					require.NoError(test, err)
+				}
+
+				gcInstance := new(MockGC)
+				gcInstance.On("Clean", context.Background())
+
+				usersGC := registry.InstrumentGC("users", gcInstance)
+				usersGC.Clean(context.Background())
+				usersGC.Clean(context.Background())
+
+				sessionsGC := registry.InstrumentGC("sessions", gcInstance)
+				sessionsGC.Clean(context.Background())
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			registry := NewRegistry(RegistryWithClock(newSteppingClock()))
+			data.prepare(test, registry)
+
+			var buffer bytes.Buffer
+			gotCount, gotErr := registry.WriteTo(&buffer)
+
+			assert.NoError(test, gotErr)
+			assert.Equal(test, int64(buffer.Len()), gotCount)
+			assertGolden(test, data.name, buffer.Bytes())
+		})
+	}
+}
+
+func TestRegistry_ServeHTTP(test *testing.T) {
+	users := cache.NewCache(cache.WithClock(clock))
+	users.SetWithCost(IntKey(1), "one", 0, 10)
+	users.SetWithCost(IntKey(2), "two", 0, 5)
+	users.Set(IntKey(3), "three", -time.Second)
+	users.Get(IntKey(1)) // nolint: errcheck
+	users.Get(IntKey(2)) // nolint: errcheck
+	users.Get(IntKey(3)) // nolint: errcheck
+	users.Get(IntKey(4)) // nolint: errcheck
+	users.SetNegative(IntKey(5), 0)
+	users.Get(IntKey(5)) // nolint: errcheck
+	users.SetWithOptions(
+		IntKey(6),
+		"six",
+		-time.Second,
+		cache.ValueWithStaleTTL(time.Minute),
+	)
+	users.Get(IntKey(6)) // nolint: errcheck
+	users.Delete(IntKey(2))
+
+	registry := NewRegistry()
+	err := registry.Register("users", users)
+	require.NoError(test, err)
+
+	request := httptest.NewRequest(http.MethodGet, "http://example.com/metrics", nil)
+	response := httptest.NewRecorder()
+	registry.ServeHTTP(response, request)
+
+	assert.Equal(test, http.StatusOK, response.Code)
+	assert.Equal(test, ContentType, response.Header().Get("Content-Type"))
+	assertGolden(test, "single_cache", response.Body.Bytes())
+}
+
+func assertGolden(test *testing.T, name string, got []byte) {
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		err := os.WriteFile(path, got, 0644)
+		require.NoError(test, err)
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(test, err)
+
+	assert.Equal(test, string(want), string(got))
+}
+
+// it advances by a quarter of a second on each call
+func newSteppingClock() models.Clock {
+	currentTime := clock()
+	return func() time.Time {
+		currentTime = currentTime.Add(time.Second / 4)
+		return currentTime
+	}
+}
+
+func clock() time.Time {
+	return time.Date(
+		2006, time.January, 2, // year, month, day
+		15, 4, 5, // hour, minute, second
+		0,        // nanosecond
+		time.UTC, // location
+	)
+}
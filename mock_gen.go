@@ -1,6 +1,10 @@
 package cache
 
 import (
+	"context"
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/eviction"
 	"github.com/thewizardplusplus/go-cache/gc"
 	"github.com/thewizardplusplus/go-cache/models"
 	hashmap "github.com/thewizardplusplus/go-hashmap"
@@ -55,3 +59,27 @@ type GC interface {
 type GCFactoryHandler interface {
 	NewGC(storage hashmap.Storage, clock models.Clock) gc.GC
 }
+
+//go:generate mockery -name=LoaderHandler -inpkg -case=underscore -testonly
+
+// LoaderHandler ...
+//
+// It's used only for mock generating.
+//
+type LoaderHandler interface {
+	Load(ctx context.Context, key hashmap.Key) (
+		data interface{},
+		ttl time.Duration,
+		err error,
+	)
+}
+
+//go:generate mockery -name=EvictionPolicy -inpkg -case=underscore -testonly
+
+// EvictionPolicy ...
+//
+// It's used only for mock generating.
+//
+type EvictionPolicy interface {
+	eviction.Policy
+}
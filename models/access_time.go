@@ -0,0 +1,34 @@
+package models
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// AccessTime ...
+//
+// It's safe for concurrent access, so it can be shared between copies
+// of the Value structure and updated without replacing the latter
+// in a storage.
+//
+type AccessTime struct {
+	unixNanoseconds atomic.Int64
+}
+
+// NewAccessTime ...
+func NewAccessTime(accessTime time.Time) *AccessTime {
+	var result AccessTime
+	result.Store(accessTime)
+
+	return &result
+}
+
+// Load ...
+func (accessTime *AccessTime) Load() time.Time {
+	return time.Unix(0, accessTime.unixNanoseconds.Load()).UTC()
+}
+
+// Store ...
+func (accessTime *AccessTime) Store(newAccessTime time.Time) {
+	accessTime.unixNanoseconds.Store(newAccessTime.UnixNano())
+}
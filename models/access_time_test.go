@@ -0,0 +1,16 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessTime(test *testing.T) {
+	accessTime := NewAccessTime(clock())
+	assert.Equal(test, clock(), accessTime.Load())
+
+	accessTime.Store(clock().Add(time.Second))
+	assert.Equal(test, clock().Add(time.Second), accessTime.Load())
+}
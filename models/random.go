@@ -0,0 +1,8 @@
+package models
+
+// Random ...
+//
+// It should return a pseudo-random number in the half-open interval [0.0, 1.0)
+// and be safe for concurrent use.
+//
+type Random func() float64
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"math"
+	"sync/atomic"
 	"time"
 )
 
@@ -8,9 +10,108 @@ import (
 type Value struct {
 	Data           interface{}
 	ExpirationTime time.Time // zero time means infinite time to live
+	Cost           int64
+
+	// zero duration means no expiration on idleness; otherwise,
+	// the access time is required
+	IdleTimeout time.Duration
+	AccessTime  *AccessTime
+
+	// values of previous generations are considered missed;
+	// the generation is changed by clearing of a cache
+	Generation uint64
+
+	Tags []string
+
+	// zero time means the value doesn't become stale before its expiration;
+	// otherwise, the revalidation flag is required
+	StaleTime    time.Time
+	Revalidation *atomic.Bool
+
+	// it's a duration of the last recomputation of the data; it's used
+	// for early expiration, and zero duration disables the latter
+	RecomputeCost time.Duration
+
+	// it's set for negative values, which store an error instead of data
+	Err error
 }
 
 // IsExpired ...
 func (value Value) IsExpired(clock Clock) bool {
-	return !value.ExpirationTime.IsZero() && clock().After(value.ExpirationTime)
+	currentTime := clock()
+	if !value.ExpirationTime.IsZero() && currentTime.After(value.ExpirationTime) {
+		return true
+	}
+
+	return value.IdleTimeout != 0 &&
+		currentTime.After(value.IdleExpirationTime())
+}
+
+// IsExpiredEarly ...
+//
+// It implements the XFetch algorithm of probabilistic early expiration:
+// a live value is considered expired with a probability that rises
+// as its expiration time gets closer, weighted by its recomputation cost.
+// The beta factor scales the probability; values greater than 1.0 favor
+// earlier expiration.
+//
+// A value without an expiration time or a recomputation cost is never
+// expired early.
+//
+func (value Value) IsExpiredEarly(
+	clock Clock,
+	beta float64,
+	random Random,
+) bool {
+	if value.ExpirationTime.IsZero() || value.RecomputeCost == 0 || beta == 0 {
+		return false
+	}
+
+	// the argument of the logarithm is in the half-open interval (0.0, 1.0]
+	gap := -float64(value.RecomputeCost) * beta * math.Log(1-random())
+	return !clock().Add(time.Duration(gap)).Before(value.ExpirationTime)
+}
+
+// IsStale ...
+//
+// It checks whether the stale time has passed. A stale value is still live
+// until its expiration time.
+//
+func (value Value) IsStale(clock Clock) bool {
+	return !value.StaleTime.IsZero() && clock().After(value.StaleTime)
+}
+
+// StaleTTL ...
+//
+// It returns a time between the stale and expiration times or zero
+// if the former isn't set.
+//
+func (value Value) StaleTTL() time.Duration {
+	if value.StaleTime.IsZero() {
+		return 0
+	}
+
+	return value.ExpirationTime.Sub(value.StaleTime)
+}
+
+// IdleExpirationTime ...
+//
+// Zero time means no expiration on idleness.
+//
+func (value Value) IdleExpirationTime() time.Time {
+	if value.IdleTimeout == 0 {
+		return time.Time{}
+	}
+
+	return value.AccessTime.Load().Add(value.IdleTimeout)
+}
+
+// Touch ...
+//
+// It postpones the expiration on idleness, if the latter is set.
+//
+func (value Value) Touch(clock Clock) {
+	if value.IdleTimeout != 0 {
+		value.AccessTime.Store(clock())
+	}
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -11,6 +12,8 @@ func TestValue_IsExpired(test *testing.T) {
 	type fields struct {
 		Data           interface{}
 		ExpirationTime time.Time
+		IdleTimeout    time.Duration
+		AccessTime     *AccessTime
 	}
 	type args struct {
 		clock Clock
@@ -66,9 +69,53 @@ func TestValue_IsExpired(test *testing.T) {
 			},
 			want: assert.False,
 		},
+		{
+			name: "access time greater than the current one minus the idle timeout",
+			fields: fields{
+				Data:           "data",
+				ExpirationTime: time.Time{},
+				IdleTimeout:    time.Second,
+				AccessTime:     NewAccessTime(clock().Add(-time.Second / 2)),
+			},
+			args: args{
+				clock: clock,
+			},
+			want: assert.False,
+		},
+		{
+			name: "access time less than the current one minus the idle timeout",
+			fields: fields{
+				Data:           "data",
+				ExpirationTime: time.Time{},
+				IdleTimeout:    time.Second,
+				AccessTime:     NewAccessTime(clock().Add(-2 * time.Second)),
+			},
+			args: args{
+				clock: clock,
+			},
+			want: assert.True,
+		},
+		{
+			name: "not expired on idleness, but expired on the expiration time",
+			fields: fields{
+				Data:           "data",
+				ExpirationTime: clock().Add(-time.Second),
+				IdleTimeout:    time.Minute,
+				AccessTime:     NewAccessTime(clock()),
+			},
+			args: args{
+				clock: clock,
+			},
+			want: assert.True,
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			value := Value{data.fields.Data, data.fields.ExpirationTime}
+			value := Value{
+				Data:           data.fields.Data,
+				ExpirationTime: data.fields.ExpirationTime,
+				IdleTimeout:    data.fields.IdleTimeout,
+				AccessTime:     data.fields.AccessTime,
+			}
 			got := value.IsExpired(data.args.clock)
 
 			data.want(test, got)
@@ -76,6 +123,245 @@ func TestValue_IsExpired(test *testing.T) {
 	}
 }
 
+func TestValue_IsExpiredEarly(test *testing.T) {
+	// with it, the gap before the expiration equals the weighted
+	// recomputation cost
+	unitRandom := func() float64 { return 1 - math.Exp(-1) }
+
+	type args struct {
+		beta   float64
+		random Random
+	}
+
+	for _, data := range []struct {
+		name  string
+		value Value
+		args  args
+		want  assert.BoolAssertionFunc
+	}{
+		{
+			name: "without an expiration time",
+			value: Value{
+				Data:          "data",
+				RecomputeCost: time.Second,
+			},
+			args: args{
+				beta:   1,
+				random: unitRandom,
+			},
+			want: assert.False,
+		},
+		{
+			name: "without a recomputation cost",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(500 * time.Millisecond),
+			},
+			args: args{
+				beta:   1,
+				random: unitRandom,
+			},
+			want: assert.False,
+		},
+		{
+			name: "with zero beta",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(500 * time.Millisecond),
+				RecomputeCost:  time.Second,
+			},
+			args: args{
+				beta:   0,
+				random: unitRandom,
+			},
+			want: assert.False,
+		},
+		{
+			name: "with a gap less than a remaining time to live",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(1500 * time.Millisecond),
+				RecomputeCost:  time.Second,
+			},
+			args: args{
+				beta:   1,
+				random: unitRandom,
+			},
+			want: assert.False,
+		},
+		{
+			name: "with a gap greater than a remaining time to live",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(500 * time.Millisecond),
+				RecomputeCost:  time.Second,
+			},
+			args: args{
+				beta:   1,
+				random: unitRandom,
+			},
+			want: assert.True,
+		},
+		{
+			name: "with a gap scaled by beta",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(1500 * time.Millisecond),
+				RecomputeCost:  time.Second,
+			},
+			args: args{
+				beta:   2,
+				random: unitRandom,
+			},
+			want: assert.True,
+		},
+		{
+			name: "with a zero random number",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(time.Nanosecond),
+				RecomputeCost:  time.Hour,
+			},
+			args: args{
+				beta:   1,
+				random: func() float64 { return 0 },
+			},
+			want: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.value.IsExpiredEarly(clock, data.args.beta, data.args.random)
+
+			data.want(test, got)
+		})
+	}
+}
+
+func TestValue_IsStale(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		value Value
+		want  assert.BoolAssertionFunc
+	}{
+		{
+			name:  "without a stale time",
+			value: Value{Data: "data", ExpirationTime: clock().Add(time.Second)},
+			want:  assert.False,
+		},
+		{
+			name: "with a stale time greater than the current one",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(2 * time.Second),
+				StaleTime:      clock().Add(time.Second),
+			},
+			want: assert.False,
+		},
+		{
+			name: "with a stale time less than the current one",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(time.Second),
+				StaleTime:      clock().Add(-time.Second),
+			},
+			want: assert.True,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.value.IsStale(clock)
+
+			data.want(test, got)
+		})
+	}
+}
+
+func TestValue_StaleTTL(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		value Value
+		want  time.Duration
+	}{
+		{
+			name:  "without a stale time",
+			value: Value{Data: "data", ExpirationTime: clock().Add(time.Second)},
+			want:  0,
+		},
+		{
+			name: "with a stale time",
+			value: Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(3 * time.Second),
+				StaleTime:      clock().Add(time.Second),
+			},
+			want: 2 * time.Second,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.value.StaleTTL()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestValue_IdleExpirationTime(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		value Value
+		want  time.Time
+	}{
+		{
+			name:  "without an idle timeout",
+			value: Value{Data: "data"},
+			want:  time.Time{},
+		},
+		{
+			name: "with an idle timeout",
+			value: Value{
+				Data:        "data",
+				IdleTimeout: time.Second,
+				AccessTime:  NewAccessTime(clock()),
+			},
+			want: clock().Add(time.Second),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.value.IdleExpirationTime()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestValue_Touch(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		value          Value
+		wantAccessTime *AccessTime
+	}{
+		{
+			name:           "without an idle timeout",
+			value:          Value{Data: "data"},
+			wantAccessTime: nil,
+		},
+		{
+			name: "with an idle timeout",
+			value: Value{
+				Data:        "data",
+				IdleTimeout: time.Second,
+				AccessTime:  NewAccessTime(clock().Add(-time.Minute)),
+			},
+			wantAccessTime: NewAccessTime(clock()),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			data.value.Touch(clock)
+
+			assert.Equal(test, data.wantAccessTime, data.value.AccessTime)
+		})
+	}
+}
+
 func clock() time.Time {
 	return time.Date(
 		2006, time.January, 2, // year, month, day
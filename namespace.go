@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"hash/fnv"
+	"io"
+	"sync"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// Namespace ...
+//
+// It returns a view of the cache with its own key space, statistics, tags,
+// event subscriptions, limits and clearing. The view shares the storage,
+// the clock, the weigher and garbage collection with the cache, including
+// garbage collection run by the NewCacheWithGC() function.
+//
+// Calls with the same name return the same view, so the options are applied
+// only by the first call. Limits and the removal listener of the cache
+// don't apply to the view, and vice versa.
+//
+// Keys of the view are stored wrapped next to keys of the cache, so
+// the storage can compare keys of different types. Therefore, the Equals()
+// method of keys of the cache must return false for a key of another type
+// instead of panicking (e.g., via a type assertion with the ok flag).
+//
+// The LiveLen() and Clear() methods of the view iterate over the whole
+// storage.
+//
+// Changes of the view are logged to the AOF of the cache (see the WithAOF()
+// option) with the name of the namespace.
+//
+func (cache Cache) Namespace(name string, options ...NamespaceOption) Cache {
+	return cache.namespaces.getOrCreate(name, func() Cache {
+		// the path is copied, so views of sibling namespaces don't share it
+		namespacePath := append(append([]string(nil), cache.namespacePath...), name)
+
+		namespaceOptions := []Option{
+			WithStorage(namespacedStorage{
+				Storage:   cache.storage,
+				namespace: newNamespace(name),
+			}),
+			WithClock(cache.clock),
+			WithWeigher(cache.weigher),
+			WithRandom(cache.random),
+			WithTTLJitter(cache.ttlJitter.percent),
+			WithTTLJitterRange(cache.ttlJitter.minJitter, cache.ttlJitter.maxJitter),
+			WithEarlyRecomputation(cache.earlyRecomputationBeta),
+			WithStaleTTL(cache.staleTTL),
+			WithRevalidator(cache.revalidator),
+			WithAOF(cache.aof),
+			withNamespacePath(namespacePath),
+		}
+		for _, option := range options {
+			namespaceOptions = append(namespaceOptions, Option(option))
+		}
+
+		return NewCache(namespaceOptions...)
+	})
+}
+
+type namespaceRegistry struct {
+	lock       sync.Mutex
+	namespaces map[string]Cache
+}
+
+func newNamespaceRegistry() *namespaceRegistry {
+	return &namespaceRegistry{namespaces: make(map[string]Cache)}
+}
+
+func (registry *namespaceRegistry) get(name string) (Cache, bool) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	namespace, ok := registry.namespaces[name]
+	return namespace, ok
+}
+
+func (registry *namespaceRegistry) all() []Cache {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	namespaces := make([]Cache, 0, len(registry.namespaces))
+	for _, namespace := range registry.namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+
+	return namespaces
+}
+
+func (registry *namespaceRegistry) getOrCreate(
+	name string,
+	create func() Cache,
+) Cache {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	namespace, ok := registry.namespaces[name]
+	if !ok {
+		namespace = create()
+		registry.namespaces[name] = namespace
+	}
+
+	return namespace
+}
+
+func withNamespacePath(namespacePath []string) Option {
+	return func(cache *Cache) {
+		cache.namespacePath = namespacePath
+	}
+}
+
+type namespace struct {
+	name string
+	hash int
+}
+
+func newNamespace(name string) namespace {
+	hash := fnv.New32a()
+	io.WriteString(hash, name) // nolint: errcheck
+
+	return namespace{name: name, hash: int(hash.Sum32())}
+}
+
+type namespacedKey struct {
+	namespace namespace
+	key       hashmap.Key
+}
+
+func (key namespacedKey) Hash() int {
+	return key.namespace.hash*31 + key.key.Hash()
+}
+
+func (key namespacedKey) Equals(other hashmap.Key) bool {
+	// a key of the cache has another type, so it's never equal
+	otherKey, ok := other.(namespacedKey)
+	return ok &&
+		key.namespace.name == otherKey.namespace.name &&
+		key.key.Equals(otherKey.key)
+}
+
+// it returns the view of the namespace at the path (the cache itself
+// for an empty path), creating missing namespaces without options
+func (cache Cache) namespaceView(namespacePath []string) Cache {
+	view := cache
+	for _, name := range namespacePath {
+		view = view.Namespace(name)
+	}
+
+	return view
+}
+
+// it unwraps the key of the storage of the cache; for a key of a namespace,
+// it returns the view of the latter and its path; false is returned
+// for a key of a namespace that isn't registered in its parent
+func (cache Cache) resolveNamespacedKey(key hashmap.Key) (
+	view Cache,
+	namespacePath []string,
+	unwrappedKey hashmap.Key,
+	ok bool,
+) {
+	view = cache
+	for {
+		wrappedKey, isWrapped := key.(namespacedKey)
+		if !isWrapped {
+			return view, namespacePath, key, true
+		}
+
+		view, ok = view.namespaces.get(wrappedKey.namespace.name)
+		if !ok {
+			return Cache{}, nil, nil, false
+		}
+
+		namespacePath = append(namespacePath, wrappedKey.namespace.name)
+		key = wrappedKey.key
+	}
+}
+
+func isNamespacedKey(key hashmap.Key) bool {
+	_, ok := key.(namespacedKey)
+	return ok
+}
+
+// it wraps keys of a namespace, so that they don't collide with keys
+// of the cache and of other namespaces
+type namespacedStorage struct {
+	hashmap.Storage
+
+	namespace namespace
+}
+
+func (storage namespacedStorage) Get(key hashmap.Key) (
+	value interface{},
+	ok bool,
+) {
+	return storage.Storage.Get(storage.wrapKey(key))
+}
+
+func (storage namespacedStorage) Iterate(handler hashmap.Handler) bool {
+	return storage.Storage.Iterate(func(key hashmap.Key, value interface{}) bool {
+		namespacedKey, ok := key.(namespacedKey)
+		if !ok || namespacedKey.namespace.name != storage.namespace.name {
+			return true
+		}
+
+		return handler(namespacedKey.key, value)
+	})
+}
+
+func (storage namespacedStorage) Set(key hashmap.Key, value interface{}) {
+	storage.Storage.Set(storage.wrapKey(key), value)
+}
+
+func (storage namespacedStorage) Delete(key hashmap.Key) {
+	storage.Storage.Delete(storage.wrapKey(key))
+}
+
+func (storage namespacedStorage) wrapKey(key hashmap.Key) namespacedKey {
+	return namespacedKey{namespace: storage.namespace, key: key}
+}
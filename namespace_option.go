@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"github.com/thewizardplusplus/go-cache/eviction"
+)
+
+// NamespaceOption ...
+type NamespaceOption func(cache *Cache)
+
+// NamespaceWithMaxSize ...
+//
+// It's the maximal count of values of the namespace, including expired
+// but not yet deleted ones. Zero means an unlimited count.
+//
+// Default: 0.
+//
+func NamespaceWithMaxSize(maxSize int) NamespaceOption {
+	return NamespaceOption(WithMaxSize(maxSize))
+}
+
+// NamespaceWithMaxCost ...
+//
+// It's the maximal total cost of values of the namespace, including expired
+// but not yet deleted ones. Zero means an unlimited cost.
+//
+// Default: 0.
+//
+func NamespaceWithMaxCost(maxCost int64) NamespaceOption {
+	return NamespaceOption(WithMaxCost(maxCost))
+}
+
+// NamespaceWithEvictionPolicy ...
+//
+// It's used only if the maximal size or cost of the namespace is set.
+// Its instance shouldn't be shared between caches or namespaces.
+//
+// Default: an instance of the eviction.LRU structure.
+//
+func NamespaceWithEvictionPolicy(evictionPolicy eviction.Policy) NamespaceOption {
+	return NamespaceOption(WithEvictionPolicy(evictionPolicy))
+}
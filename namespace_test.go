@@ -0,0 +1,290 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-cache/eviction"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_Namespace(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	orders := cache.Namespace("orders")
+	users := cache.Namespace("users")
+
+	cache.Set(IntKey(23), "cache", 0)
+	orders.Set(IntKey(23), "orders", 0)
+	orders.Set(IntKey(42), "orders", 0)
+
+	for _, data := range []struct {
+		name     string
+		cache    Cache
+		wantData interface{}
+		wantErr  error
+		wantLen  int
+		wantKeys []int
+	}{
+		{
+			name:     "cache",
+			cache:    cache,
+			wantData: "cache",
+			wantErr:  nil,
+			wantLen:  1,
+			wantKeys: []int{23},
+		},
+		{
+			name:     "namespace",
+			cache:    orders,
+			wantData: "orders",
+			wantErr:  nil,
+			wantLen:  2,
+			wantKeys: []int{23, 42},
+		},
+		{
+			name:     "same namespace",
+			cache:    cache.Namespace("orders"),
+			wantData: "orders",
+			wantErr:  nil,
+			wantLen:  2,
+			wantKeys: []int{23, 42},
+		},
+		{
+			name:     "other namespace",
+			cache:    users,
+			wantData: nil,
+			wantErr:  ErrKeyMissed,
+			wantLen:  0,
+			wantKeys: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotData, gotErr := data.cache.Get(IntKey(23))
+
+			assert.Equal(test, data.wantData, gotData)
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(test, data.wantLen, data.cache.Len())
+			assert.Equal(test, data.wantLen, data.cache.LiveLen())
+			assert.Equal(test, data.wantKeys, iteratedIntKeys(data.cache))
+		})
+	}
+}
+
+func TestCache_Namespace_withStats(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	orders := cache.Namespace("orders")
+
+	cache.Set(IntKey(23), "cache", 0)
+	orders.Get(IntKey(23)) // nolint: errcheck
+	orders.Set(IntKey(23), "orders", 0)
+	orders.Get(IntKey(23)) // nolint: errcheck
+	orders.Delete(IntKey(23))
+
+	assert.Equal(test, Stats{Sets: 1}, cache.Stats())
+	assert.Equal(
+		test,
+		Stats{Hits: 1, Misses: 1, Sets: 1, Deletes: 1},
+		orders.Stats(),
+	)
+}
+
+func TestCache_Namespace_withClear(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	orders := cache.Namespace("orders")
+	users := cache.Namespace("users")
+	for _, instance := range []Cache{cache, orders, users} {
+		instance.Set(IntKey(23), "one", 0)
+		instance.Set(IntKey(42), "two", 0)
+	}
+
+	orders.Clear()
+
+	assert.Equal(test, 2, cache.Len())
+	assert.Equal(test, 0, orders.Len())
+	assert.Equal(test, 2, users.Len())
+	assert.Equal(test, 4, countStoredValues(cache.storage))
+
+	cache.Clear()
+
+	assert.Equal(test, 0, cache.Len())
+	assert.Equal(test, 0, orders.Len())
+	assert.Equal(test, 2, users.Len())
+	assert.Equal(test, 2, countStoredValues(cache.storage))
+
+	data, err := users.Get(IntKey(23))
+	assert.Equal(test, "one", data)
+	assert.NoError(test, err)
+}
+
+func TestCache_Namespace_withLimits(test *testing.T) {
+	cache := NewCache(WithClock(clock), WithMaxSize(1))
+	orders := cache.Namespace("orders", NamespaceWithMaxSize(2))
+	users := cache.Namespace(
+		"users",
+		NamespaceWithMaxCost(10),
+		NamespaceWithEvictionPolicy(eviction.NewLRU()),
+	)
+
+	cache.Set(IntKey(1), "one", 0)
+	for key := 1; key <= 3; key++ {
+		orders.Set(IntKey(key), "data", 0)
+		users.SetWithCost(IntKey(key), "data", 0, 5)
+	}
+
+	assert.Equal(test, 1, cache.Len())
+	assert.Equal(test, 2, orders.Len())
+	assert.Equal(test, []int{2, 3}, iteratedIntKeys(orders))
+	assert.Equal(test, 2, users.Len())
+	assert.Equal(test, []int{2, 3}, iteratedIntKeys(users))
+	assert.Equal(test, int64(10), users.Cost())
+}
+
+func TestCache_Namespace_withGC(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const gcPeriod = 10 * time.Millisecond
+	cache := NewCacheWithGC(
+		ctx,
+		WithGCAndGCFactory(func(storage hashmap.Storage, clock models.Clock) gc.GC {
+			return gc.NewTotalGC(storage, gc.TotalGCWithClock(clock))
+		}),
+		WithGCAndGCPeriod(gcPeriod),
+	)
+	orders := cache.Namespace("orders")
+	archive := orders.Namespace("archive")
+
+	events, cancelSubscription := orders.Subscribe(EventFilter{Types: EventTypeExpire})
+	defer cancelSubscription()
+
+	cache.Set(IntKey(23), "cache", 0)
+	orders.Set(IntKey(23), "orders", 0)
+	orders.SetWithOptions(IntKey(42), "orders", -time.Second, ValueWithTags("old"))
+	archive.Set(IntKey(42), "archive", -time.Second)
+
+	require.Eventually(test, func() bool {
+		return orders.Len() == 1 && archive.Len() == 0
+	}, time.Second, gcPeriod)
+	event := <-events
+
+	assert.Equal(test, IntKey(42), event.Key)
+	assert.Equal(test, 1, cache.Len())
+	assert.Equal(test, int64(1), orders.Stats().GCDeletes)
+	assert.Equal(test, int64(1), archive.Stats().GCDeletes)
+	assert.Equal(test, int64(0), cache.Stats().GCDeletes)
+	assert.Empty(test, orders.tags.keysByTag)
+	assert.Equal(test, 2, countStoredValues(cache.storage))
+}
+
+func TestCache_Namespace_withCollidingKeys(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	orders := cache.Namespace("orders")
+
+	// the keys get the same hash in the shared storage
+	namespaceKey := fixedHashKey{id: 1, hash: 23}
+	cacheKey := fixedHashKey{id: 2, hash: newNamespace("orders").hash*31 + 23}
+	cache.Set(cacheKey, "cache", 0)
+	orders.Set(namespaceKey, "orders", 0)
+
+	gotData, gotErr := cache.Get(cacheKey)
+	assert.Equal(test, "cache", gotData)
+	assert.NoError(test, gotErr)
+
+	gotData, gotErr = orders.Get(namespaceKey)
+	assert.Equal(test, "orders", gotData)
+	assert.NoError(test, gotErr)
+
+	_, gotErr = cache.Get(namespaceKey)
+	assert.Equal(test, ErrKeyMissed, gotErr)
+	assert.Equal(test, 2, countStoredValues(cache.storage))
+}
+
+func Test_namespacedKey(test *testing.T) {
+	orders, users := newNamespace("orders"), newNamespace("users")
+	key := namespacedKey{namespace: orders, key: IntKey(23)}
+
+	for _, data := range []struct {
+		name      string
+		other     hashmap.Key
+		wantEqual assert.BoolAssertionFunc
+	}{
+		{
+			name:      "same key",
+			other:     namespacedKey{namespace: newNamespace("orders"), key: IntKey(23)},
+			wantEqual: assert.True,
+		},
+		{
+			name:      "other key",
+			other:     namespacedKey{namespace: orders, key: IntKey(42)},
+			wantEqual: assert.False,
+		},
+		{
+			name:      "other namespace",
+			other:     namespacedKey{namespace: users, key: IntKey(23)},
+			wantEqual: assert.False,
+		},
+		{
+			name:      "not namespaced key",
+			other:     IntKey(23),
+			wantEqual: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			data.wantEqual(test, key.Equals(data.other))
+		})
+	}
+
+	assert.Equal(
+		test,
+		key.Hash(),
+		namespacedKey{namespace: newNamespace("orders"), key: IntKey(23)}.Hash(),
+	)
+	assert.NotEqual(
+		test,
+		key.Hash(),
+		namespacedKey{namespace: users, key: IntKey(23)}.Hash(),
+	)
+}
+
+// its Equals() method is safe for keys of other types, as it's required
+// by namespaces
+type fixedHashKey struct {
+	id   int
+	hash int
+}
+
+func (key fixedHashKey) Hash() int {
+	return key.hash
+}
+
+func (key fixedHashKey) Equals(other hashmap.Key) bool {
+	otherKey, ok := other.(fixedHashKey)
+	return ok && key == otherKey
+}
+
+func iteratedIntKeys(cache Cache) []int {
+	var keys []int
+	cache.Iterate(context.Background(), func(key hashmap.Key, data interface{}) bool {
+		keys = append(keys, int(key.(IntKey)))
+		return true
+	})
+	sort.Ints(keys)
+
+	return keys
+}
+
+func countStoredValues(storage hashmap.Storage) int {
+	var count int
+	storage.Iterate(func(key hashmap.Key, data interface{}) bool {
+		count++
+		return true
+	})
+
+	return count
+}
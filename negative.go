@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// ErrKeyNegative ...
+//
+// It's returned for a negative value set by the SetNegative() method.
+// The NegativeError type wraps it, so it can be checked via the errors.Is()
+// function for any negative value.
+//
+var ErrKeyNegative = errors.New("key negative")
+
+// NegativeError ...
+//
+// It's returned for a negative value set by the SetError() method.
+// It wraps both ErrKeyNegative and the stored error.
+//
+type NegativeError struct {
+	Err error
+}
+
+// Error ...
+func (err NegativeError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrKeyNegative, err.Err)
+}
+
+// Unwrap ...
+func (err NegativeError) Unwrap() []error {
+	return []error{ErrKeyNegative, err.Err}
+}
+
+// NegativeLoader ...
+//
+// It wraps the loader, so that the Cache.GetOrLoad() method stores
+// a negative value with the specified time to live, when the loader returns
+// an error for which the predicate is true (e.g., "not found").
+// Zero time to live means infinite one.
+//
+// For such an error, the resulting loader returns the NegativeError one.
+//
+func NegativeLoader(
+	loader Loader,
+	negativeTTL time.Duration,
+	isNegative func(err error) bool,
+) Loader {
+	return func(ctx context.Context, key hashmap.Key) (
+		data interface{},
+		ttl time.Duration,
+		err error,
+	) {
+		data, ttl, err = loader(ctx, key)
+		if err != nil && isNegative(err) {
+			return nil, negativeTTL, NegativeError{Err: err}
+		}
+
+		return data, ttl, err
+	}
+}
+
+// SetNegative ...
+//
+// It stores a marker of a known absent key (a negative value), so that
+// getting of the key returns ErrKeyNegative. Zero time to live means
+// infinite one.
+//
+// A negative value has zero cost, unless it's set via the options. It's
+// counted by the Len() method, but isn't iterated.
+//
+func (cache Cache) SetNegative(
+	key hashmap.Key,
+	ttl time.Duration,
+	options ...ValueOption,
+) {
+	cache.setNegative(key, ErrKeyNegative, ttl, options)
+}
+
+// SetError ...
+//
+// It's the same as the SetNegative() method, but getting of the key returns
+// the NegativeError error that wraps the specified one. If the latter is nil,
+// ErrKeyNegative is returned.
+//
+func (cache Cache) SetError(
+	key hashmap.Key,
+	err error,
+	ttl time.Duration,
+	options ...ValueOption,
+) {
+	var negativeErr error = ErrKeyNegative
+	if err != nil {
+		negativeErr = NegativeError{Err: err}
+	}
+
+	cache.setNegative(key, negativeErr, ttl, options)
+}
+
+func (cache Cache) setNegative(
+	key hashmap.Key,
+	err error,
+	ttl time.Duration,
+	options []ValueOption,
+) {
+	// the weigher isn't ready for nil data
+	options = append([]ValueOption{ValueWithCost(0)}, options...)
+
+	value := cache.newValue(key, nil, ttl, options)
+	value.Err = err
+
+	cache.setValue(key, value)
+}
+
+func isAbsent(err error) bool {
+	return err == ErrKeyMissed || err == ErrKeyExpired || err == errKeyExpiredEarly
+}
@@ -0,0 +1,233 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestNegativeError(test *testing.T) {
+	err := NegativeError{Err: errNotFound}
+
+	assert.EqualError(test, err, "key negative: not found")
+	assert.True(test, errors.Is(err, ErrKeyNegative))
+	assert.True(test, errors.Is(err, errNotFound))
+}
+
+func TestCache_negativeValues(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		prepare  func(cache Cache)
+		wantData interface{}
+		wantErr  error
+	}{
+		{
+			name: "SetNegative/success",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), time.Second)
+			},
+			wantData: nil,
+			wantErr:  ErrKeyNegative,
+		},
+		{
+			name: "SetNegative/expired",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), -time.Second)
+			},
+			wantData: nil,
+			wantErr:  ErrKeyExpired,
+		},
+		{
+			name: "SetNegative/replacing of data",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+				cache.SetNegative(IntKey(23), 0)
+			},
+			wantData: nil,
+			wantErr:  ErrKeyNegative,
+		},
+		{
+			name: "SetError/with an error",
+			prepare: func(cache Cache) {
+				cache.SetError(IntKey(23), errNotFound, 0)
+			},
+			wantData: nil,
+			wantErr:  NegativeError{Err: errNotFound},
+		},
+		{
+			name: "SetError/without an error",
+			prepare: func(cache Cache) {
+				cache.SetError(IntKey(23), nil, 0)
+			},
+			wantData: nil,
+			wantErr:  ErrKeyNegative,
+		},
+		{
+			name: "Set/replacing of a negative value",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), 0)
+				cache.Set(IntKey(23), "data", 0)
+			},
+			wantData: "data",
+			wantErr:  nil,
+		},
+		{
+			name: "SetIfAbsent/replacing of a negative value",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), 0)
+				cache.SetIfAbsent(IntKey(23), "data", 0)
+			},
+			wantData: "data",
+			wantErr:  nil,
+		},
+		{
+			name: "Replace/keeping of a negative value",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), 0)
+				cache.Replace(IntKey(23), "data", 0) // nolint: errcheck
+			},
+			wantData: nil,
+			wantErr:  ErrKeyNegative,
+		},
+		{
+			name: "Expire/updating of a negative value",
+			prepare: func(cache Cache) {
+				cache.SetNegative(IntKey(23), 0)
+				cache.Expire(IntKey(23), -time.Second) // nolint: errcheck
+			},
+			wantData: nil,
+			wantErr:  ErrKeyExpired,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(
+				WithClock(clock),
+				WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+					return int64(len(data.(string)))
+				}),
+			)
+			data.prepare(cache)
+
+			gotData, gotErr := cache.Get(IntKey(23))
+			gotResults := cache.GetMany([]hashmap.Key{IntKey(23)})
+
+			assert.Equal(test, data.wantData, gotData)
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(
+				test,
+				[]BatchResult{{Data: data.wantData, Err: data.wantErr}},
+				gotResults,
+			)
+		})
+	}
+}
+
+func TestCache_SetNegative_withIteration(test *testing.T) {
+	cache := NewCache(
+		WithClock(clock),
+		WithWeigher(func(key hashmap.Key, data interface{}) int64 {
+			return int64(len(data.(string)))
+		}),
+	)
+	cache.Set(IntKey(23), "data", 0)
+	cache.SetNegative(IntKey(42), 0)
+	cache.Get(IntKey(42)) // nolint: errcheck
+
+	var gotKeys []hashmap.Key
+	cache.Iterate(context.Background(), func(key hashmap.Key, data interface{}) bool {
+		gotKeys = append(gotKeys, key)
+		return true
+	})
+
+	assert.Equal(test, []hashmap.Key{IntKey(23)}, gotKeys)
+	assert.Equal(test, 2, cache.Len())
+	assert.Equal(test, 1, cache.LiveLen())
+	assert.Equal(test, int64(4), cache.Cost())
+	assert.Equal(test, int64(1), cache.Stats().NegativeHits)
+}
+
+func TestCache_GetOrLoad_withNegativeLoader(test *testing.T) {
+	var loaderCallCount atomic.Int64
+	loader := NegativeLoader(
+		func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			loaderCallCount.Add(1)
+			if key.(IntKey) == 23 {
+				return nil, 0, errNotFound
+			}
+			if key.(IntKey) == 42 {
+				return nil, 0, context.DeadlineExceeded
+			}
+
+			return "data", time.Minute, nil
+		},
+		time.Minute,
+		func(err error) bool { return errors.Is(err, errNotFound) },
+	)
+
+	for _, data := range []struct {
+		name                string
+		key                 hashmap.Key
+		wantData            interface{}
+		wantErr             error
+		wantLoaderCallCount int64
+		wantLen             int
+	}{
+		{
+			name:                "with a negative error",
+			key:                 IntKey(23),
+			wantData:            nil,
+			wantErr:             NegativeError{Err: errNotFound},
+			wantLoaderCallCount: 1,
+			wantLen:             1,
+		},
+		{
+			name:                "with a not negative error",
+			key:                 IntKey(42),
+			wantData:            nil,
+			wantErr:             context.DeadlineExceeded,
+			wantLoaderCallCount: 2,
+			wantLen:             0,
+		},
+		{
+			name:                "without an error",
+			key:                 IntKey(12),
+			wantData:            "data",
+			wantErr:             nil,
+			wantLoaderCallCount: 1,
+			wantLen:             1,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			loaderCallCount.Store(0)
+
+			currentTime := clock()
+			cache := NewCache(WithClock(func() time.Time { return currentTime }))
+			for i := 0; i < 2; i++ {
+				gotData, gotErr := cache.GetOrLoad(context.Background(), data.key, loader)
+
+				assert.Equal(test, data.wantData, gotData)
+				assert.Equal(test, data.wantErr, gotErr)
+			}
+
+			assert.Equal(test, data.wantLoaderCallCount, loaderCallCount.Load())
+			assert.Equal(test, data.wantLen, cache.Len())
+
+			// the values expire with their times to live
+			currentTime = currentTime.Add(time.Hour)
+			cache.GetOrLoad(context.Background(), data.key, loader) // nolint: errcheck
+
+			assert.Equal(test, data.wantLoaderCallCount+1, loaderCallCount.Load())
+		})
+	}
+}
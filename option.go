@@ -1,6 +1,9 @@
 package cache
 
 import (
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/eviction"
 	"github.com/thewizardplusplus/go-cache/models"
 	hashmap "github.com/thewizardplusplus/go-hashmap"
 )
@@ -28,3 +31,205 @@ func WithClock(clock models.Clock) Option {
 		cache.clock = clock
 	}
 }
+
+// WithMaxSize ...
+//
+// It's the maximal count of values, including expired but not yet deleted
+// ones. Zero means an unlimited count.
+//
+// Default: 0.
+//
+func WithMaxSize(maxSize int) Option {
+	return func(cache *Cache) {
+		cache.maxSize = maxSize
+	}
+}
+
+// WithWeigher ...
+//
+// Default: nil, i.e., costs of values are zero unless they are set explicitly.
+//
+func WithWeigher(weigher Weigher) Option {
+	return func(cache *Cache) {
+		cache.weigher = weigher
+	}
+}
+
+// WithMaxCost ...
+//
+// It's the maximal total cost of values, including expired but not yet
+// deleted ones. Zero means an unlimited cost.
+//
+// Default: 0.
+//
+func WithMaxCost(maxCost int64) Option {
+	return func(cache *Cache) {
+		cache.maxCost = maxCost
+	}
+}
+
+// WithEvictionPolicy ...
+//
+// It's used only if the maximal size or cost is set. Its instance shouldn't be shared
+// between caches.
+//
+// Default: an instance of the eviction.LRU structure.
+//
+func WithEvictionPolicy(evictionPolicy eviction.Policy) Option {
+	return func(cache *Cache) {
+		cache.evictionPolicy = evictionPolicy
+	}
+}
+
+// WithRemovalListener ...
+//
+// The listener is called asynchronously, in a separate goroutine for each
+// removed value, so an order of calls isn't guaranteed.
+//
+// Default: nil.
+//
+func WithRemovalListener(removalListener RemovalListener) Option {
+	return func(cache *Cache) {
+		cache.removalListener = removalListener
+		cache.isRemovalListenerSync = false
+	}
+}
+
+// WithSyncRemovalListener ...
+//
+// The listener is called synchronously by an operation that removed a value,
+// so it slows the operation down.
+//
+// Default: nil.
+//
+func WithSyncRemovalListener(removalListener RemovalListener) Option {
+	return func(cache *Cache) {
+		cache.removalListener = removalListener
+		cache.isRemovalListenerSync = true
+	}
+}
+
+// WithRandom ...
+//
+// It's used for jitter of times to live and for early expiration of values
+// (see the WithEarlyRecomputation() option). It should be safe for concurrent
+// use.
+//
+// Default: the rand.Float64() function.
+//
+func WithRandom(random models.Random) Option {
+	return func(cache *Cache) {
+		cache.random = random
+	}
+}
+
+// WithTTLJitter ...
+//
+// It randomly scales a time to live by a factor from the interval
+// [1 - percent, 1 + percent] when an expiration time is calculated
+// from the former, so that values set at the same time don't expire
+// at the same time. E.g., 0.1 means ±10% of the time to live.
+//
+// Zero and negative times to live aren't jittered, and a jittered time to live
+// is at least 1 ns. It's applied before the jitter range, if both are set.
+//
+// Default: 0, i.e., no jitter.
+//
+func WithTTLJitter(percent float64) Option {
+	return func(cache *Cache) {
+		cache.ttlJitter.percent = percent
+	}
+}
+
+// WithTTLJitterRange ...
+//
+// It randomly adds a duration from the interval [minJitter, maxJitter]
+// to a time to live when an expiration time is calculated from the latter.
+// The bounds can be negative.
+//
+// Zero and negative times to live aren't jittered, and a jittered time to live
+// is at least 1 ns.
+//
+// Default: [0, 0], i.e., no jitter.
+//
+func WithTTLJitterRange(minJitter time.Duration, maxJitter time.Duration) Option {
+	return func(cache *Cache) {
+		cache.ttlJitter.minJitter = minJitter
+		cache.ttlJitter.maxJitter = maxJitter
+	}
+}
+
+// WithEarlyRecomputation ...
+//
+// It enables probabilistic early expiration of live values (the XFetch
+// algorithm), so that a single caller of the Get() or GetOrLoad() method
+// recomputes a hot value before its expiration, instead of all callers
+// waiting for recomputation after it. The probability rises as
+// the expiration time gets closer, weighted by a recomputation cost
+// of the value (see the ValueWithRecomputeCost() option).
+//
+// The beta factor scales the probability: 1.0 is the recommended value,
+// and greater values favor earlier recomputation. Zero disables it.
+// The random source is set by the WithRandom() option.
+//
+// Default: 0.
+//
+func WithEarlyRecomputation(beta float64) Option {
+	return func(cache *Cache) {
+		cache.earlyRecomputationBeta = beta
+	}
+}
+
+// WithStaleTTL ...
+//
+// It's a default stale time to live of values, including loaded ones
+// (see the ValueWithStaleTTL() option for details).
+//
+// Default: 0, i.e., no stale serving.
+//
+func WithStaleTTL(staleTTL time.Duration) Option {
+	return func(cache *Cache) {
+		cache.staleTTL = staleTTL
+	}
+}
+
+// WithRevalidator ...
+//
+// It's used by the Get() and GetMany() methods to revalidate stale values
+// in background (stale-while-revalidate). The revalidation is started once
+// for each stale value; if it fails, the stale value is served until its
+// expiration (stale-if-error). The GetOrLoad() method uses its own loader
+// instead.
+//
+// A revalidated value keeps the stale time to live, the idle time to live,
+// the tags and, if the weigher isn't set, the cost of the stale one.
+//
+// Default: nil, i.e., stale values are served without revalidation.
+//
+func WithRevalidator(revalidator Loader) Option {
+	return func(cache *Cache) {
+		cache.revalidator = revalidator
+	}
+}
+
+// WithAOF ...
+//
+// It sets the AOF, to which all the changes of values are logged
+// (see the OpenAOF() function and the ReplayAOF() method), including ones
+// of namespaces (see the Namespace() method).
+//
+// Accesses of values, including the Touch() method, aren't logged,
+// so expiration on idleness is restarted on the replay (see the ReplayAOF()
+// method for details).
+//
+// An error of the logging, including one of the codec, is kept by the AOF
+// and stops the logging; it's returned by the AOF.Sync() and AOF.Close()
+// methods.
+//
+// Default: nil, i.e., changes aren't logged.
+//
+func WithAOF(aof *AOF) Option {
+	return func(cache *Cache) {
+		cache.aof = aof
+	}
+}
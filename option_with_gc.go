@@ -1,8 +1,10 @@
 package cache
 
 import (
+	"math/rand"
 	"time"
 
+	"github.com/thewizardplusplus/go-cache/eviction"
 	"github.com/thewizardplusplus/go-cache/gc"
 	"github.com/thewizardplusplus/go-cache/models"
 	hashmap "github.com/thewizardplusplus/go-hashmap"
@@ -13,10 +15,28 @@ type GCFactory func(storage hashmap.Storage, clock models.Clock) gc.GC
 
 // ConfigWithGC ...
 type ConfigWithGC struct {
-	storage   hashmap.Storage
-	clock     models.Clock
-	gcFactory GCFactory
-	gcPeriod  time.Duration
+	storage        hashmap.Storage
+	clock          models.Clock
+	maxSize        int
+	weigher        Weigher
+	maxCost        int64
+	evictionPolicy eviction.Policy
+
+	removalListener       RemovalListener
+	isRemovalListenerSync bool
+
+	random    models.Random
+	ttlJitter ttlJitter
+
+	earlyRecomputationBeta float64
+	staleTTL               time.Duration
+	revalidator            Loader
+
+	aof *AOF
+
+	gcFactory       GCFactory
+	gcPeriod        time.Duration
+	gcReportHandler gc.ReportHandler
 }
 
 // OptionWithGC ...
@@ -45,6 +65,203 @@ func WithGCAndClock(clock models.Clock) OptionWithGC {
 	}
 }
 
+// WithGCAndMaxSize ...
+//
+// It's the maximal count of values, including expired but not yet deleted
+// ones. Zero means an unlimited count.
+//
+// Default: 0.
+//
+func WithGCAndMaxSize(maxSize int) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.maxSize = maxSize
+	}
+}
+
+// WithGCAndWeigher ...
+//
+// Default: nil, i.e., costs of values are zero unless they are set explicitly.
+//
+func WithGCAndWeigher(weigher Weigher) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.weigher = weigher
+	}
+}
+
+// WithGCAndMaxCost ...
+//
+// It's the maximal total cost of values, including expired but not yet
+// deleted ones. Zero means an unlimited cost.
+//
+// Default: 0.
+//
+func WithGCAndMaxCost(maxCost int64) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.maxCost = maxCost
+	}
+}
+
+// WithGCAndEvictionPolicy ...
+//
+// It's used only if the maximal size or cost is set. Its instance shouldn't be shared
+// between caches.
+//
+// Default: an instance of the eviction.LRU structure.
+//
+func WithGCAndEvictionPolicy(evictionPolicy eviction.Policy) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.evictionPolicy = evictionPolicy
+	}
+}
+
+// WithGCAndRemovalListener ...
+//
+// The listener is called asynchronously, in a separate goroutine for each
+// removed value, so an order of calls isn't guaranteed.
+//
+// Default: nil.
+//
+func WithGCAndRemovalListener(removalListener RemovalListener) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.removalListener = removalListener
+		config.isRemovalListenerSync = false
+	}
+}
+
+// WithGCAndSyncRemovalListener ...
+//
+// The listener is called synchronously by an operation that removed a value,
+// including garbage collection, so it slows the operation down.
+//
+// Default: nil.
+//
+func WithGCAndSyncRemovalListener(removalListener RemovalListener) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.removalListener = removalListener
+		config.isRemovalListenerSync = true
+	}
+}
+
+// WithGCAndRandom ...
+//
+// It's used for jitter of times to live and for early expiration of values
+// (see the WithGCAndEarlyRecomputation() option). It should be safe
+// for concurrent use.
+//
+// Default: the rand.Float64() function.
+//
+func WithGCAndRandom(random models.Random) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.random = random
+	}
+}
+
+// WithGCAndTTLJitter ...
+//
+// It randomly scales a time to live by a factor from the interval
+// [1 - percent, 1 + percent] when an expiration time is calculated
+// from the former, so that values set at the same time don't expire
+// at the same time. E.g., 0.1 means ±10% of the time to live.
+//
+// Zero and negative times to live aren't jittered, and a jittered time to live
+// is at least 1 ns. It's applied before the jitter range, if both are set.
+//
+// Default: 0, i.e., no jitter.
+//
+func WithGCAndTTLJitter(percent float64) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.ttlJitter.percent = percent
+	}
+}
+
+// WithGCAndTTLJitterRange ...
+//
+// It randomly adds a duration from the interval [minJitter, maxJitter]
+// to a time to live when an expiration time is calculated from the latter.
+// The bounds can be negative.
+//
+// Zero and negative times to live aren't jittered, and a jittered time to live
+// is at least 1 ns.
+//
+// Default: [0, 0], i.e., no jitter.
+//
+func WithGCAndTTLJitterRange(
+	minJitter time.Duration,
+	maxJitter time.Duration,
+) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.ttlJitter.minJitter = minJitter
+		config.ttlJitter.maxJitter = maxJitter
+	}
+}
+
+// WithGCAndEarlyRecomputation ...
+//
+// It enables probabilistic early expiration of live values (the XFetch
+// algorithm), so that a single caller of the Get() or GetOrLoad() method
+// recomputes a hot value before its expiration, instead of all callers
+// waiting for recomputation after it. The probability rises as
+// the expiration time gets closer, weighted by a recomputation cost
+// of the value (see the ValueWithRecomputeCost() option).
+//
+// The beta factor scales the probability: 1.0 is the recommended value,
+// and greater values favor earlier recomputation. Zero disables it.
+// The random source is set by the WithGCAndRandom() option.
+//
+// Default: 0.
+//
+func WithGCAndEarlyRecomputation(beta float64) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.earlyRecomputationBeta = beta
+	}
+}
+
+// WithGCAndStaleTTL ...
+//
+// It's a default stale time to live of values, including loaded ones
+// (see the ValueWithStaleTTL() option for details). Garbage collection
+// deletes values only after their stale time to live expires.
+//
+// Default: 0, i.e., no stale serving.
+//
+func WithGCAndStaleTTL(staleTTL time.Duration) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.staleTTL = staleTTL
+	}
+}
+
+// WithGCAndRevalidator ...
+//
+// It's used by the Get() and GetMany() methods to revalidate stale values
+// in background (stale-while-revalidate). The revalidation is started once
+// for each stale value; if it fails, the stale value is served until its
+// expiration (stale-if-error). The GetOrLoad() method uses its own loader
+// instead.
+//
+// A revalidated value keeps the stale time to live, the idle time to live,
+// the tags and, if the weigher isn't set, the cost of the stale one.
+//
+// Default: nil, i.e., stale values are served without revalidation.
+//
+func WithGCAndRevalidator(revalidator Loader) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.revalidator = revalidator
+	}
+}
+
+// WithGCAndAOF ...
+//
+// It sets the AOF, to which all the changes of values are logged
+// (see the WithAOF() option for details).
+//
+// Default: nil, i.e., changes aren't logged.
+//
+func WithGCAndAOF(aof *AOF) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.aof = aof
+	}
+}
+
 // WithGCAndGCFactory ...
 //
 // Default: a factory that produces an instance of the gc.PartialGC structure
@@ -66,11 +283,24 @@ func WithGCAndGCPeriod(gcPeriod time.Duration) OptionWithGC {
 	}
 }
 
+// WithGCAndGCReportHandler ...
+//
+// It receives reports of garbage collection if the latter implements
+// the gc.ReportingGC interface. See the gc.RunWithReportHandler() function
+// for details.
+//
+func WithGCAndGCReportHandler(gcReportHandler gc.ReportHandler) OptionWithGC {
+	return func(config *ConfigWithGC) {
+		config.gcReportHandler = gcReportHandler
+	}
+}
+
 func newConfigWithGC(options []OptionWithGC) ConfigWithGC {
 	// default config
 	config := ConfigWithGC{
 		storage: hashmap.NewConcurrentHashMap(),
 		clock:   time.Now,
+		random:  rand.Float64,
 		gcFactory: func(storage hashmap.Storage, clock models.Clock) gc.GC {
 			return gc.NewPartialGC(storage, gc.PartialGCWithClock(clock))
 		},
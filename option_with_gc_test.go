@@ -1,12 +1,14 @@
 package cache
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-cache/eviction"
 	"github.com/thewizardplusplus/go-cache/gc"
 	"github.com/thewizardplusplus/go-cache/models"
 	hashmap "github.com/thewizardplusplus/go-hashmap"
@@ -18,12 +20,25 @@ func Test_newConfigWithGC(test *testing.T) {
 	}
 
 	for _, data := range []struct {
-		name          string
-		args          args
-		wantStorage   hashmap.Storage
-		wantClockTime time.Time
-		wantGCType    gc.GC
-		wantGCPeriod  time.Duration
+		name               string
+		args               args
+		wantStorage        hashmap.Storage
+		wantClockTime      time.Time
+		wantMaxSize        int
+		wantWeigher        assert.ValueAssertionFunc
+		wantMaxCost        int64
+		wantEvictionPolicy eviction.Policy
+		wantTTLJitter      ttlJitter
+		wantGCType         gc.GC
+		wantGCPeriod       time.Duration
+
+		wantRemovalListener        assert.ValueAssertionFunc
+		wantIsRemovalListenerSync  bool
+		wantEarlyRecomputationBeta float64
+		wantStaleTTL               time.Duration
+		wantRevalidator            assert.ValueAssertionFunc
+		wantAOF                    *AOF
+		wantGCReportHandler        assert.ValueAssertionFunc
 	}{
 		{
 			name: "with the default config",
@@ -55,6 +70,150 @@ func Test_newConfigWithGC(test *testing.T) {
 			wantGCType:    gc.PartialGC{},
 			wantGCPeriod:  100 * time.Millisecond,
 		},
+		{
+			name: "with the set maximal size",
+			args: args{
+				options: []OptionWithGC{WithGCAndMaxSize(23)},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantMaxSize:   23,
+			wantGCType:    gc.PartialGC{},
+			wantGCPeriod:  100 * time.Millisecond,
+		},
+		{
+			name: "with the set weigher",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndWeigher(func(key hashmap.Key, data interface{}) int64 {
+						return 23
+					}),
+				},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantWeigher:   assert.NotNil,
+			wantGCType:    gc.PartialGC{},
+			wantGCPeriod:  100 * time.Millisecond,
+		},
+		{
+			name: "with the set maximal cost",
+			args: args{
+				options: []OptionWithGC{WithGCAndMaxCost(23)},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantMaxCost:   23,
+			wantGCType:    gc.PartialGC{},
+			wantGCPeriod:  100 * time.Millisecond,
+		},
+		{
+			name: "with the set eviction policy",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndEvictionPolicy(new(MockEvictionPolicy)),
+				},
+			},
+			wantStorage:        hashmap.NewConcurrentHashMap(),
+			wantClockTime:      time.Now(),
+			wantEvictionPolicy: new(MockEvictionPolicy),
+			wantGCType:         gc.PartialGC{},
+			wantGCPeriod:       100 * time.Millisecond,
+		},
+		{
+			name: "with the set removal listener",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndRemovalListener(
+						func(key hashmap.Key, data interface{}, reason RemovalReason) {},
+					),
+				},
+			},
+			wantStorage:               hashmap.NewConcurrentHashMap(),
+			wantClockTime:             time.Now(),
+			wantGCType:                gc.PartialGC{},
+			wantGCPeriod:              100 * time.Millisecond,
+			wantRemovalListener:       assert.NotNil,
+			wantIsRemovalListenerSync: false,
+		},
+		{
+			name: "with the set sync removal listener",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndSyncRemovalListener(
+						func(key hashmap.Key, data interface{}, reason RemovalReason) {},
+					),
+				},
+			},
+			wantStorage:               hashmap.NewConcurrentHashMap(),
+			wantClockTime:             time.Now(),
+			wantGCType:                gc.PartialGC{},
+			wantGCPeriod:              100 * time.Millisecond,
+			wantRemovalListener:       assert.NotNil,
+			wantIsRemovalListenerSync: true,
+		},
+		{
+			name: "with the set TTL jitter",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndRandom(func() float64 { return 0.5 }),
+					WithGCAndTTLJitter(0.1),
+					WithGCAndTTLJitterRange(-time.Second, 2*time.Second),
+				},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantTTLJitter: ttlJitter{
+				percent:   0.1,
+				minJitter: -time.Second,
+				maxJitter: 2 * time.Second,
+			},
+			wantGCType:   gc.PartialGC{},
+			wantGCPeriod: 100 * time.Millisecond,
+		},
+		{
+			name: "with the set early recomputation",
+			args: args{
+				options: []OptionWithGC{WithGCAndEarlyRecomputation(1.5)},
+			},
+			wantStorage:                hashmap.NewConcurrentHashMap(),
+			wantClockTime:              time.Now(),
+			wantEarlyRecomputationBeta: 1.5,
+			wantGCType:                 gc.PartialGC{},
+			wantGCPeriod:               100 * time.Millisecond,
+		},
+		{
+			name: "with the set stale serving",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndStaleTTL(time.Minute),
+					WithGCAndRevalidator(func(ctx context.Context, key hashmap.Key) (
+						data interface{},
+						ttl time.Duration,
+						err error,
+					) {
+						return "data", 0, nil
+					}),
+				},
+			},
+			wantStorage:     hashmap.NewConcurrentHashMap(),
+			wantClockTime:   time.Now(),
+			wantStaleTTL:    time.Minute,
+			wantRevalidator: assert.NotNil,
+			wantGCType:      gc.PartialGC{},
+			wantGCPeriod:    100 * time.Millisecond,
+		},
+		{
+			name: "with the set AOF",
+			args: args{
+				options: []OptionWithGC{WithGCAndAOF(&AOF{path: "cache.aof"})},
+			},
+			wantStorage:   hashmap.NewConcurrentHashMap(),
+			wantClockTime: time.Now(),
+			wantAOF:       &AOF{path: "cache.aof"},
+			wantGCType:    gc.PartialGC{},
+			wantGCPeriod:  100 * time.Millisecond,
+		},
 		{
 			name: "with the set GC factory",
 			args: args{
@@ -81,6 +240,19 @@ func Test_newConfigWithGC(test *testing.T) {
 			wantGCType:    gc.PartialGC{},
 			wantGCPeriod:  23 * time.Second,
 		},
+		{
+			name: "with the set GC report handler",
+			args: args{
+				options: []OptionWithGC{
+					WithGCAndGCReportHandler(func(report gc.Report) {}),
+				},
+			},
+			wantStorage:         hashmap.NewConcurrentHashMap(),
+			wantClockTime:       time.Now(),
+			wantGCType:          gc.PartialGC{},
+			wantGCPeriod:        100 * time.Millisecond,
+			wantGCReportHandler: assert.NotNil,
+		},
 		{
 			name: "with the set config",
 			args: args{
@@ -111,6 +283,10 @@ func Test_newConfigWithGC(test *testing.T) {
 				mock.AssertExpectationsForObjects(test, got.storage)
 			}
 			assert.Equal(test, data.wantStorage, got.storage)
+			assert.Equal(test, data.wantMaxSize, got.maxSize)
+			assert.Equal(test, data.wantMaxCost, got.maxCost)
+			assert.Equal(test, data.wantEvictionPolicy, got.evictionPolicy)
+			assert.Equal(test, data.wantTTLJitter, got.ttlJitter)
 			assert.Equal(test, data.wantGCPeriod, got.gcPeriod)
 
 			// don't use the reflect.Value.Pointer() method for checks below;
@@ -120,6 +296,43 @@ func Test_newConfigWithGC(test *testing.T) {
 
 			require.NotNil(test, got.clock)
 			assert.WithinDuration(test, data.wantClockTime, got.clock(), time.Hour)
+			assert.NotNil(test, got.random)
+
+			if data.wantWeigher != nil {
+				data.wantWeigher(test, got.weigher)
+			} else {
+				assert.Nil(test, got.weigher)
+			}
+
+			if data.wantRemovalListener != nil {
+				data.wantRemovalListener(test, got.removalListener)
+			} else {
+				assert.Nil(test, got.removalListener)
+			}
+			assert.Equal(
+				test,
+				data.wantIsRemovalListenerSync,
+				got.isRemovalListenerSync,
+			)
+			assert.Equal(
+				test,
+				data.wantEarlyRecomputationBeta,
+				got.earlyRecomputationBeta,
+			)
+			assert.Equal(test, data.wantStaleTTL, got.staleTTL)
+
+			if data.wantRevalidator != nil {
+				data.wantRevalidator(test, got.revalidator)
+			} else {
+				assert.Nil(test, got.revalidator)
+			}
+			assert.Equal(test, data.wantAOF, got.aof)
+
+			if data.wantGCReportHandler != nil {
+				data.wantGCReportHandler(test, got.gcReportHandler)
+			} else {
+				assert.Nil(test, got.gcReportHandler)
+			}
 
 			require.NotNil(test, got.gcFactory)
 			assert.IsType(test, data.wantGCType, got.gcFactory(got.storage, got.clock))
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"fmt"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// RemovalReason ...
+type RemovalReason int
+
+// ...
+const (
+	// RemovalReasonDeleted means explicit deletion, e.g., via the Delete() method
+	RemovalReasonDeleted RemovalReason = iota
+	// RemovalReasonReplaced means overwriting by setting of new data
+	RemovalReasonReplaced
+	// RemovalReasonExpired means that a time to live of the value expired;
+	// it takes precedence over other reasons except RemovalReasonCleared
+	RemovalReasonExpired
+	// RemovalReasonEvicted means eviction by the eviction policy
+	RemovalReasonEvicted
+	// RemovalReasonCleared means deletion via the Clear() method;
+	// it takes precedence over other reasons
+	RemovalReasonCleared
+)
+
+// String ...
+func (reason RemovalReason) String() string {
+	switch reason {
+	case RemovalReasonDeleted:
+		return "deleted"
+	case RemovalReasonReplaced:
+		return "replaced"
+	case RemovalReasonExpired:
+		return "expired"
+	case RemovalReasonEvicted:
+		return "evicted"
+	case RemovalReasonCleared:
+		return "cleared"
+	default:
+		return fmt.Sprintf("RemovalReason(%d)", int(reason))
+	}
+}
+
+// RemovalListener ...
+//
+// It's called exactly once for each value removed from the cache.
+// It's called after a lock of the key is released, so it can call methods
+// of the cache.
+//
+type RemovalListener func(key hashmap.Key, data interface{}, reason RemovalReason)
+
+type removal struct {
+	key    hashmap.Key
+	data   interface{}
+	reason RemovalReason
+}
+
+func (cache Cache) notifyOfRemovals(removals []removal) {
+	for _, removal := range removals {
+		if cache.isRemovalListenerSync {
+			cache.removalListener(removal.key, removal.data, removal.reason)
+		} else {
+			go cache.removalListener(removal.key, removal.data, removal.reason)
+		}
+	}
+}
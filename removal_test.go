@@ -0,0 +1,345 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/gc"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestRemovalReason_String(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		reason RemovalReason
+		want   string
+	}{
+		{
+			name:   "deleted",
+			reason: RemovalReasonDeleted,
+			want:   "deleted",
+		},
+		{
+			name:   "replaced",
+			reason: RemovalReasonReplaced,
+			want:   "replaced",
+		},
+		{
+			name:   "expired",
+			reason: RemovalReasonExpired,
+			want:   "expired",
+		},
+		{
+			name:   "evicted",
+			reason: RemovalReasonEvicted,
+			want:   "evicted",
+		},
+		{
+			name:   "cleared",
+			reason: RemovalReasonCleared,
+			want:   "cleared",
+		},
+		{
+			name:   "unknown",
+			reason: RemovalReason(23),
+			want:   "RemovalReason(23)",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.reason.String()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestCache_removalListener(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		options      []Option
+		prepare      func(cache Cache)
+		remove       func(cache Cache)
+		wantRemovals []removal
+	}{
+		{
+			name: "Delete/with a missed key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(42), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.Delete(IntKey(23))
+			},
+			wantRemovals: nil,
+		},
+		{
+			name: "Delete/with a present key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.Delete(IntKey(23))
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonDeleted},
+			},
+		},
+		{
+			name: "Delete/with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			remove: func(cache Cache) {
+				cache.Delete(IntKey(23))
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonExpired},
+			},
+		},
+		{
+			name: "Set/with a present key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.Set(IntKey(23), "data #2", 0)
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonReplaced},
+			},
+		},
+		{
+			name: "Set/with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			remove: func(cache Cache) {
+				cache.Set(IntKey(23), "data #2", 0)
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonExpired},
+			},
+		},
+		{
+			name:    "SetWithCost/with a cost exceeding the maximal one",
+			options: []Option{WithMaxCost(10)},
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.SetWithCost(IntKey(23), "data #2", 0, 23)
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonReplaced},
+			},
+		},
+		{
+			name:    "Set/with eviction",
+			options: []Option{WithMaxSize(1)},
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.Set(IntKey(42), "data #2", 0)
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonEvicted},
+			},
+		},
+		{
+			name: "GetWithGC/with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			remove: func(cache Cache) {
+				cache.GetWithGC(IntKey(23)) // nolint: errcheck
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonExpired},
+			},
+		},
+		{
+			name: "IterateWithGC/with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+			},
+			remove: func(cache Cache) {
+				cache.IterateWithGC(
+					context.Background(),
+					func(key hashmap.Key, data interface{}) bool { return true },
+				)
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonExpired},
+			},
+		},
+		{
+			name: "Clear/with present keys",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.Clear()
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonCleared},
+			},
+		},
+		{
+			name: "GetAndDelete/with a present key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.GetAndDelete(IntKey(23)) // nolint: errcheck
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonDeleted},
+			},
+		},
+		{
+			name: "Update/without keeping",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.Update(IntKey(23), func(data interface{}, ok bool) (
+					newData interface{},
+					ttl time.Duration,
+					keep bool,
+				) {
+					return nil, 0, false
+				}) // nolint: errcheck
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonDeleted},
+			},
+		},
+		{
+			name: "SetMany/with equal keys",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.SetMany(
+					[]Entry{
+						{Key: IntKey(23), Data: "data #2"},
+						{Key: IntKey(23), Data: "data #3"},
+					},
+					0,
+				)
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonReplaced},
+				{key: IntKey(23), data: "data #2", reason: RemovalReasonReplaced},
+			},
+		},
+		{
+			name: "DeleteMany/with equal keys",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+			},
+			remove: func(cache Cache) {
+				cache.DeleteMany([]hashmap.Key{IntKey(23), IntKey(23)})
+			},
+			wantRemovals: []removal{
+				{key: IntKey(23), data: "data #1", reason: RemovalReasonDeleted},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var gotRemovals []removal
+			options := append([]Option{
+				WithClock(clock),
+				WithWeigher(func(key hashmap.Key, data interface{}) int64 { return 1 }),
+			}, data.options...)
+			cache := NewCache(options...)
+			data.prepare(cache)
+
+			cache.removalListener =
+				func(key hashmap.Key, data interface{}, reason RemovalReason) {
+					gotRemovals = append(gotRemovals, removal{key, data, reason})
+				}
+			cache.isRemovalListenerSync = true
+			data.remove(cache)
+
+			assert.Equal(test, data.wantRemovals, gotRemovals)
+		})
+	}
+}
+
+func TestCache_removalListener_async(test *testing.T) {
+	gotRemovals := make(chan removal, 1)
+	cache := NewCache(
+		WithClock(clock),
+		WithRemovalListener(
+			func(key hashmap.Key, data interface{}, reason RemovalReason) {
+				gotRemovals <- removal{key, data, reason}
+			},
+		),
+	)
+	cache.Set(IntKey(23), "data", 0)
+	cache.Delete(IntKey(23))
+
+	wantRemoval :=
+		removal{key: IntKey(23), data: "data", reason: RemovalReasonDeleted}
+	assert.Equal(test, wantRemoval, <-gotRemovals)
+}
+
+func TestCache_removalListener_withConcurrentGC(test *testing.T) {
+	const keyCount = 100
+
+	var lock sync.Mutex
+	removalCounts := make(map[hashmap.Key]int)
+	cache := NewCache(
+		WithClock(clock),
+		WithSyncRemovalListener(
+			func(key hashmap.Key, data interface{}, reason RemovalReason) {
+				lock.Lock()
+				defer lock.Unlock()
+
+				removalCounts[key]++
+			},
+		),
+	)
+	for i := 0; i < keyCount; i++ {
+		cache.Set(IntKey(i), i, -time.Second)
+	}
+
+	gcInstance := gc.NewTotalGC(
+		gcStorage{Storage: cache.storage, cache: cache},
+		gc.TotalGCWithClock(cache.clock),
+	)
+
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(3)
+
+	go func() {
+		defer waitGroup.Done()
+
+		gcInstance.Clean(context.Background())
+	}()
+	go func() {
+		defer waitGroup.Done()
+
+		for i := 0; i < keyCount; i++ {
+			cache.GetWithGC(IntKey(i)) // nolint: errcheck
+		}
+	}()
+	go func() {
+		defer waitGroup.Done()
+
+		cache.IterateWithGC(
+			context.Background(),
+			func(key hashmap.Key, data interface{}) bool { return true },
+		)
+	}()
+	waitGroup.Wait()
+
+	assert.Len(test, removalCounts, keyCount)
+	for key, count := range removalCounts {
+		assert.Equal(test, 1, count, "key: %v", key)
+	}
+}
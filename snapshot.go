@@ -0,0 +1,509 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+const (
+	snapshotMagic   = "GOCACHE-SNAPSHOT"
+	snapshotVersion = 1
+)
+
+const (
+	snapshotEndMarker byte = iota
+	snapshotEntryMarker
+	// it's followed by the path of the namespace before the entry
+	snapshotNamespacedEntryMarker
+)
+
+// ...
+var (
+	ErrInvalidSnapshot            = errors.New("invalid snapshot")
+	ErrUnsupportedSnapshotVersion = errors.New("unsupported snapshot version")
+	ErrSnapshotChecksumMismatch   = errors.New("snapshot checksum mismatch")
+)
+
+var snapshotChecksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// SaveSnapshot ...
+//
+// It streams all the live values with their keys to the writer, including
+// ones of namespaces (see the Namespace() method) with their names. Expiration
+// times are saved as absolute ones, so they are preserved on loading.
+// Negative values aren't saved.
+//
+// A namespace can also be saved separately by the same method of its view.
+//
+// The snapshot starts with a header that contains its format version
+// and ends with a CRC-32 checksum of its content.
+//
+func (cache Cache) SaveSnapshot(writer io.Writer, codec SnapshotCodec) error {
+	cache = cache.withCurrentTime(cache.clock())
+
+	snapshotWriter := newSnapshotWriter(writer)
+	snapshotWriter.write([]byte(snapshotMagic))
+	snapshotWriter.writeUvarint(snapshotVersion)
+
+	var entryCount uint64
+	var err error
+	cache.storage.Iterate(func(key hashmap.Key, data interface{}) bool {
+		value := data.(models.Value)
+		view, namespacePath, key, ok := cache.resolveNamespacedKey(key)
+		if !ok ||
+			view.isCleared(value) ||
+			value.IsExpired(cache.clock) ||
+			value.Err != nil {
+			return true
+		}
+
+		if len(namespacePath) == 0 {
+			snapshotWriter.write([]byte{snapshotEntryMarker})
+		} else {
+			snapshotWriter.write([]byte{snapshotNamespacedEntryMarker})
+			snapshotWriter.writeNamespacePath(namespacePath)
+		}
+		if err = snapshotWriter.writeEntry(key, value, codec); err != nil {
+			return false
+		}
+
+		entryCount++
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	snapshotWriter.write([]byte{snapshotEndMarker})
+	snapshotWriter.writeUvarint(entryCount)
+	return snapshotWriter.finish()
+}
+
+// LoadSnapshot ...
+//
+// It sets values from the snapshot saved by the SaveSnapshot() method,
+// skipping ones that have already expired. The snapshot is verified
+// as a whole before setting of any value, so it's buffered in memory.
+//
+// Values of namespaces are set to the views with the same names, which
+// are created without options if missing, so ones with options should
+// be created before the loading.
+//
+// The error can be ErrInvalidSnapshot (including for a truncated snapshot),
+// ErrUnsupportedSnapshotVersion, ErrSnapshotChecksumMismatch or an error
+// of the reader or the codec.
+//
+func (cache Cache) LoadSnapshot(reader io.Reader, codec SnapshotCodec) error {
+	snapshotReader := newSnapshotReader(reader)
+	magic := snapshotReader.readFull(uint64(len(snapshotMagic)))
+	if snapshotReader.err != nil {
+		return snapshotReader.err
+	}
+	if string(magic) != snapshotMagic {
+		return ErrInvalidSnapshot
+	}
+
+	version := snapshotReader.readUvarint()
+	if snapshotReader.err != nil {
+		return snapshotReader.err
+	}
+	if version != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedSnapshotVersion, version)
+	}
+
+	var entries []snapshotEntry
+	for {
+		marker := snapshotReader.readMarker()
+		if snapshotReader.err != nil {
+			return snapshotReader.err
+		}
+		if marker == snapshotEndMarker {
+			break
+		}
+
+		var namespacePath []string
+		switch marker {
+		case snapshotEntryMarker:
+		case snapshotNamespacedEntryMarker:
+			namespacePath = snapshotReader.readNamespacePath()
+		default:
+			return fmt.Errorf("%w: unknown marker %d", ErrInvalidSnapshot, marker)
+		}
+
+		entry, err := snapshotReader.readEntry(codec)
+		if err != nil {
+			return err
+		}
+
+		entry.namespacePath = namespacePath
+
+		entries = append(entries, entry)
+	}
+
+	entryCount := snapshotReader.readUvarint()
+	if snapshotReader.err == nil && entryCount != uint64(len(entries)) {
+		return fmt.Errorf("%w: wrong entry count", ErrInvalidSnapshot)
+	}
+	if err := snapshotReader.verifyChecksum(); err != nil {
+		return err
+	}
+
+	currentTime := cache.clock()
+	for _, entry := range entries {
+		// the namespaces are got from the cache itself, so the clock
+		// of created ones isn't frozen
+		view := cache.namespaceView(entry.namespacePath).withCurrentTime(currentTime)
+		if !entry.value.IsExpired(view.clock) {
+			view.setValue(entry.key, entry.value)
+		}
+	}
+
+	return nil
+}
+
+type snapshotEntry struct {
+	key   hashmap.Key
+	value models.Value
+	// it's empty for a value of the cache itself
+	namespacePath []string
+}
+
+type snapshotWriter struct {
+	writer   *bufio.Writer
+	checksum hash.Hash32
+	buffer   [binary.MaxVarintLen64]byte
+	err      error
+}
+
+func newSnapshotWriter(writer io.Writer) *snapshotWriter {
+	return &snapshotWriter{
+		writer:   bufio.NewWriter(writer),
+		checksum: crc32.New(snapshotChecksumTable),
+	}
+}
+
+// it returns an error of the writer or the codec; the former is also kept
+// for writing of following data
+func (writer *snapshotWriter) writeEntry(
+	key hashmap.Key,
+	value models.Value,
+	codec SnapshotCodec,
+) error {
+	if err := writer.writeKey(key, codec); err != nil {
+		return err
+	}
+
+	encodedData, err := codec.Data.Encode(value.Data)
+	if err != nil {
+		return fmt.Errorf("unable to encode the data: %w", err)
+	}
+
+	var accessTime time.Time
+	if value.AccessTime != nil {
+		accessTime = value.AccessTime.Load()
+	}
+
+	writer.writeBytes(encodedData)
+	writer.writeTime(value.ExpirationTime)
+	writer.writeTime(value.StaleTime)
+	writer.writeVarint(value.Cost)
+	writer.writeVarint(int64(value.IdleTimeout))
+	writer.writeTime(accessTime)
+	writer.writeVarint(int64(value.RecomputeCost))
+	writer.writeUvarint(uint64(len(value.Tags)))
+	for _, tag := range value.Tags {
+		writer.writeBytes([]byte(tag))
+	}
+
+	return writer.err
+}
+
+// it's the same as the writeEntry() method, but for the key only
+func (writer *snapshotWriter) writeKey(key hashmap.Key, codec SnapshotCodec) error {
+	encodedKey, err := codec.Key.Encode(key)
+	if err != nil {
+		return fmt.Errorf("unable to encode the key: %w", err)
+	}
+
+	writer.writeBytes(encodedKey)
+	return writer.err
+}
+
+func (writer *snapshotWriter) writeNamespacePath(namespacePath []string) {
+	writer.writeUvarint(uint64(len(namespacePath)))
+	for _, name := range namespacePath {
+		writer.writeBytes([]byte(name))
+	}
+}
+
+func (writer *snapshotWriter) write(data []byte) {
+	if writer.err != nil {
+		return
+	}
+
+	writer.checksum.Write(data) // nolint: errcheck
+	_, writer.err = writer.writer.Write(data)
+}
+
+func (writer *snapshotWriter) writeUvarint(number uint64) {
+	length := binary.PutUvarint(writer.buffer[:], number)
+	writer.write(writer.buffer[:length])
+}
+
+func (writer *snapshotWriter) writeVarint(number int64) {
+	length := binary.PutVarint(writer.buffer[:], number)
+	writer.write(writer.buffer[:length])
+}
+
+func (writer *snapshotWriter) writeBytes(data []byte) {
+	writer.writeUvarint(uint64(len(data)))
+	writer.write(data)
+}
+
+// zero time is written as zero
+func (writer *snapshotWriter) writeTime(timestamp time.Time) {
+	var unixNanoseconds int64
+	if !timestamp.IsZero() {
+		unixNanoseconds = timestamp.UnixNano()
+	}
+
+	writer.writeVarint(unixNanoseconds)
+}
+
+// the checksum isn't included in itself
+func (writer *snapshotWriter) finish() error {
+	if writer.err != nil {
+		return writer.err
+	}
+
+	checksum := writer.checksum.Sum32()
+	binary.BigEndian.PutUint32(writer.buffer[:4], checksum)
+	if _, err := writer.writer.Write(writer.buffer[:4]); err != nil {
+		return err
+	}
+
+	return writer.flush()
+}
+
+func (writer *snapshotWriter) flush() error {
+	if writer.err != nil {
+		return writer.err
+	}
+
+	return writer.writer.Flush()
+}
+
+// like the snapshotWriter structure, it keeps the first error, so following
+// reading is skipped, and checking can be done once
+type snapshotReader struct {
+	reader   *bufio.Reader
+	checksum hash.Hash32
+	err      error
+}
+
+func newSnapshotReader(reader io.Reader) *snapshotReader {
+	return &snapshotReader{
+		reader:   bufio.NewReader(reader),
+		checksum: crc32.New(snapshotChecksumTable),
+	}
+}
+
+// it returns an error of the reader or the codec
+func (reader *snapshotReader) readEntry(
+	codec SnapshotCodec,
+) (snapshotEntry, error) {
+	key, err := reader.readKey(codec)
+	if err != nil {
+		return snapshotEntry{}, err
+	}
+
+	encodedData := reader.readBytes()
+
+	var value models.Value
+	value.ExpirationTime = reader.readTime()
+	value.StaleTime = reader.readTime()
+	value.Cost = reader.readVarint()
+	value.IdleTimeout = time.Duration(reader.readVarint())
+	accessTime := reader.readTime()
+	value.RecomputeCost = time.Duration(reader.readVarint())
+	tagCount := reader.readUvarint()
+	for index := uint64(0); index < tagCount && reader.err == nil; index++ {
+		value.Tags = append(value.Tags, string(reader.readBytes()))
+	}
+	if reader.err != nil {
+		return snapshotEntry{}, reader.err
+	}
+
+	value.Data, err = codec.Data.Decode(encodedData)
+	if err != nil {
+		return snapshotEntry{}, fmt.Errorf("unable to decode the data: %w", err)
+	}
+
+	if value.IdleTimeout != 0 {
+		value.AccessTime = models.NewAccessTime(accessTime)
+	}
+	if !value.StaleTime.IsZero() {
+		value.Revalidation = new(atomic.Bool)
+	}
+
+	return snapshotEntry{key: key, value: value}, nil
+}
+
+// it's the same as the readEntry() method, but for the key only
+func (reader *snapshotReader) readKey(codec SnapshotCodec) (hashmap.Key, error) {
+	encodedKey := reader.readBytes()
+	if reader.err != nil {
+		return nil, reader.err
+	}
+
+	key, err := codec.Key.Decode(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the key: %w", err)
+	}
+
+	hashableKey, ok := key.(hashmap.Key)
+	if !ok {
+		return nil, fmt.Errorf(
+			"unable to decode the key: %T doesn't implement hashmap.Key",
+			key,
+		)
+	}
+
+	return hashableKey, nil
+}
+
+// ReadByte ...
+//
+// It implements the io.ByteReader interface for the binary.ReadUvarint()
+// and binary.ReadVarint() functions.
+//
+func (reader *snapshotReader) ReadByte() (byte, error) {
+	data, err := reader.reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	reader.checksum.Write([]byte{data}) // nolint: errcheck
+	return data, nil
+}
+
+func (reader *snapshotReader) readMarker() byte {
+	if reader.err != nil {
+		return 0
+	}
+
+	var marker byte
+	marker, reader.err = reader.ReadByte()
+	reader.err = wrapSnapshotReadingError(reader.err)
+
+	return marker
+}
+
+func (reader *snapshotReader) readUvarint() uint64 {
+	if reader.err != nil {
+		return 0
+	}
+
+	var number uint64
+	number, reader.err = binary.ReadUvarint(reader)
+	reader.err = wrapSnapshotReadingError(reader.err)
+
+	return number
+}
+
+func (reader *snapshotReader) readVarint() int64 {
+	if reader.err != nil {
+		return 0
+	}
+
+	var number int64
+	number, reader.err = binary.ReadVarint(reader)
+	reader.err = wrapSnapshotReadingError(reader.err)
+
+	return number
+}
+
+// the data is copied gradually, so a corrupted length doesn't cause
+// a huge allocation
+func (reader *snapshotReader) readFull(length uint64) []byte {
+	if reader.err != nil {
+		return nil
+	}
+	if length > math.MaxInt64 {
+		reader.err = fmt.Errorf("%w: too long data", ErrInvalidSnapshot)
+		return nil
+	}
+
+	var buffer bytes.Buffer
+	_, reader.err = io.CopyN(
+		io.MultiWriter(&buffer, reader.checksum),
+		reader.reader,
+		int64(length),
+	)
+	reader.err = wrapSnapshotReadingError(reader.err)
+
+	return buffer.Bytes()
+}
+
+func (reader *snapshotReader) readBytes() []byte {
+	return reader.readFull(reader.readUvarint())
+}
+
+func (reader *snapshotReader) readNamespacePath() []string {
+	var namespacePath []string
+	nameCount := reader.readUvarint()
+	for index := uint64(0); index < nameCount && reader.err == nil; index++ {
+		namespacePath = append(namespacePath, string(reader.readBytes()))
+	}
+
+	return namespacePath
+}
+
+func (reader *snapshotReader) readTime() time.Time {
+	unixNanoseconds := reader.readVarint()
+	if unixNanoseconds == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, unixNanoseconds).UTC()
+}
+
+// the checksum isn't included in itself
+func (reader *snapshotReader) verifyChecksum() error {
+	if reader.err != nil {
+		return reader.err
+	}
+
+	wantChecksum := reader.checksum.Sum32()
+
+	var checksum [4]byte
+	if _, err := io.ReadFull(reader.reader, checksum[:]); err != nil {
+		return wrapSnapshotReadingError(err)
+	}
+	if binary.BigEndian.Uint32(checksum[:]) != wantChecksum {
+		return ErrSnapshotChecksumMismatch
+	}
+
+	return nil
+}
+
+// an unexpected end of the reader means a truncated snapshot
+func wrapSnapshotReadingError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, io.ErrUnexpectedEOF)
+	}
+
+	return err
+}
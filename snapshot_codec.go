@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+)
+
+// Codec ...
+//
+// It's used to serialize keys or data of a snapshot.
+//
+type Codec interface {
+	Encode(value interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+// SnapshotCodec ...
+//
+// It's a pair of codecs for keys and data of a snapshot. The key codec should
+// decode keys to implementations of the hashmap.Key interface.
+//
+type SnapshotCodec struct {
+	Key  Codec
+	Data Codec
+}
+
+// GobCodec ...
+//
+// It encodes values via the encoding/gob package. Values are encoded
+// as interfaces, so their concrete types should be registered via
+// the gob.Register() function.
+//
+type GobCodec struct{}
+
+type gobValue struct {
+	Value interface{}
+}
+
+// Encode ...
+func (codec GobCodec) Encode(value interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(gobValue{Value: value}); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Decode ...
+func (codec GobCodec) Decode(data []byte) (interface{}, error) {
+	var value gobValue
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value.Value, nil
+}
+
+// JSONCodec ...
+//
+// It encodes values via the encoding/json package.
+//
+type JSONCodec struct {
+	newValue func() interface{}
+}
+
+// NewJSONCodec ...
+//
+// The factory should return a pointer to a new value of a type to decode to;
+// the decoded value is the one it points to. If the factory is nil, values
+// are decoded to default types of the encoding/json package
+// (e.g., float64 for numbers).
+//
+func NewJSONCodec(newValue func() interface{}) JSONCodec {
+	return JSONCodec{newValue: newValue}
+}
+
+// Encode ...
+func (codec JSONCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode ...
+func (codec JSONCodec) Decode(data []byte) (interface{}, error) {
+	if codec.newValue == nil {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+
+		return value, nil
+	}
+
+	value := codec.newValue()
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, err
+	}
+
+	return reflect.ValueOf(value).Elem().Interface(), nil
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	gob.Register(IntKey(0))
+}
+
+func TestCodecs(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		codec   Codec
+		value   interface{}
+		want    interface{}
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "GobCodec/with a registered type",
+			codec:   GobCodec{},
+			value:   IntKey(23),
+			want:    IntKey(23),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "GobCodec/with a built-in type",
+			codec:   GobCodec{},
+			value:   "data",
+			want:    "data",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "GobCodec/with an unregistered type",
+			codec:   GobCodec{},
+			value:   struct{ Field int }{Field: 23},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+		{
+			name:    "JSONCodec/with a factory",
+			codec:   NewJSONCodec(func() interface{} { return new(IntKey) }),
+			value:   IntKey(23),
+			want:    IntKey(23),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "JSONCodec/without a factory",
+			codec:   NewJSONCodec(nil),
+			value:   map[string]interface{}{"number": 23},
+			want:    map[string]interface{}{"number": 23.0},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "JSONCodec/with an unsupported type",
+			codec:   NewJSONCodec(nil),
+			value:   make(chan int),
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			encoded, err := data.codec.Encode(data.value)
+			if err != nil {
+				data.wantErr(test, err)
+				return
+			}
+
+			got, err := data.codec.Decode(encoded)
+			require.NoError(test, err)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, nil)
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_snapshot(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		codec SnapshotCodec
+	}{
+		{
+			name:  "gob",
+			codec: SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}},
+		},
+		{
+			name: "JSON",
+			codec: SnapshotCodec{
+				Key:  NewJSONCodec(func() interface{} { return new(IntKey) }),
+				Data: NewJSONCodec(func() interface{} { return new(string) }),
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			cache.Set(IntKey(1), "persistent", 0)
+			cache.Set(IntKey(2), "live", time.Minute)
+			cache.Set(IntKey(3), "expired", -time.Minute)
+			cache.SetNegative(IntKey(4), time.Minute)
+			cache.SetWithOptions(
+				IntKey(5),
+				"with options",
+				time.Minute,
+				ValueWithCost(23),
+				ValueWithIdleTTL(30*time.Second),
+				ValueWithTags("product:23"),
+			)
+			cache.SetWithOptions(
+				IntKey(6),
+				"with a stale TTL",
+				time.Minute,
+				ValueWithStaleTTL(time.Minute),
+			)
+			cache.Namespace("orders").Set(IntKey(7), "namespaced", 0)
+
+			var snapshot bytes.Buffer
+			err := cache.SaveSnapshot(&snapshot, data.codec)
+			require.NoError(test, err)
+
+			currentTime := clock().Add(10 * time.Second)
+			restoredCache := NewCache(WithClock(func() time.Time { return currentTime }))
+			err = restoredCache.LoadSnapshot(&snapshot, data.codec)
+			require.NoError(test, err)
+
+			assert.Equal(test, []int{1, 2, 5, 6}, iteratedIntKeys(restoredCache))
+			assert.Equal(test, int64(23), restoredCache.Cost())
+
+			for key, wantTTL := range map[IntKey]time.Duration{
+				1: NoExpiration,
+				2: 50 * time.Second,
+				5: 20 * time.Second, // the idle time to live is the nearest one
+				6: 110 * time.Second,
+			} {
+				gotTTL, err := restoredCache.TTL(key)
+
+				assert.Equal(test, wantTTL, gotTTL, "key %d", key)
+				assert.NoError(test, err)
+			}
+
+			// the stale time to live is preserved
+			currentTime = clock().Add(90 * time.Second)
+			gotData, gotErr := restoredCache.Get(IntKey(6))
+
+			assert.Equal(test, "with a stale TTL", gotData)
+			assert.Equal(test, ErrKeyStale, gotErr)
+
+			// the tags are preserved
+			currentTime = clock().Add(10 * time.Second)
+			assert.Equal(test, 1, restoredCache.InvalidateTag("product:23"))
+		})
+	}
+}
+
+func TestCache_LoadSnapshot_withExpiredValues(test *testing.T) {
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	cache := NewCache(WithClock(clock))
+	cache.Set(IntKey(23), "one", time.Minute)
+	cache.Set(IntKey(42), "two", time.Hour)
+
+	var snapshot bytes.Buffer
+	err := cache.SaveSnapshot(&snapshot, codec)
+	require.NoError(test, err)
+
+	restoredCache := NewCache(WithClock(func() time.Time {
+		return clock().Add(2 * time.Minute)
+	}))
+	err = restoredCache.LoadSnapshot(&snapshot, codec)
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{42}, iteratedIntKeys(restoredCache))
+	assert.Equal(test, 1, restoredCache.Len())
+}
+
+func TestCache_SaveSnapshot_withError(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		data    interface{}
+		writer  func() *failingWriter
+		codec   SnapshotCodec
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "error of the writer",
+			data:   "data",
+			writer: func() *failingWriter { return &failingWriter{err: errWriting} },
+			codec:  SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}},
+			wantErr: func(t assert.TestingT, err error, msgAndArgs ...interface{}) bool {
+				return assert.Equal(t, errWriting, err, msgAndArgs...)
+			},
+		},
+		{
+			name:   "error of the codec",
+			data:   make(chan int),
+			writer: func() *failingWriter { return new(failingWriter) },
+			codec:  SnapshotCodec{Key: GobCodec{}, Data: NewJSONCodec(nil)},
+			wantErr: func(t assert.TestingT, err error, msgAndArgs ...interface{}) bool {
+				return assert.EqualError(
+					t,
+					err,
+					"unable to encode the data: json: unsupported type: chan int",
+					msgAndArgs...,
+				)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			cache.Set(IntKey(23), data.data, 0)
+
+			err := cache.SaveSnapshot(data.writer(), data.codec)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestCache_LoadSnapshot_withError(test *testing.T) {
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	cache := NewCache(WithClock(clock))
+	cache.Set(IntKey(23), "one", 0)
+	cache.Set(IntKey(42), "two", 0)
+
+	var buffer bytes.Buffer
+	err := cache.SaveSnapshot(&buffer, codec)
+	require.NoError(test, err)
+	snapshot := buffer.Bytes()
+
+	for _, data := range []struct {
+		name    string
+		reader  func() *bytes.Reader
+		codec   SnapshotCodec
+		wantErr error
+	}{
+		{
+			name: "invalid magic",
+			reader: func() *bytes.Reader {
+				return bytes.NewReader([]byte("not a snapshot at all"))
+			},
+			codec:   codec,
+			wantErr: ErrInvalidSnapshot,
+		},
+		{
+			name: "unsupported version",
+			reader: func() *bytes.Reader {
+				return bytes.NewReader(append([]byte(snapshotMagic), 2))
+			},
+			codec:   codec,
+			wantErr: ErrUnsupportedSnapshotVersion,
+		},
+		{
+			name: "unknown marker",
+			reader: func() *bytes.Reader {
+				return bytes.NewReader(append([]byte(snapshotMagic), snapshotVersion, 23))
+			},
+			codec:   codec,
+			wantErr: ErrInvalidSnapshot,
+		},
+		{
+			name: "corrupted content",
+			reader: func() *bytes.Reader {
+				corrupted := bytes.Clone(snapshot)
+				corrupted[bytes.Index(corrupted, []byte("two"))] = 'T'
+
+				return bytes.NewReader(corrupted)
+			},
+			codec:   codec,
+			wantErr: ErrSnapshotChecksumMismatch,
+		},
+		{
+			name: "truncated snapshot/without the checksum",
+			reader: func() *bytes.Reader {
+				return bytes.NewReader(snapshot[:len(snapshot)-2])
+			},
+			codec:   codec,
+			wantErr: ErrInvalidSnapshot,
+		},
+		{
+			name: "truncated snapshot/in the middle",
+			reader: func() *bytes.Reader {
+				return bytes.NewReader(snapshot[:len(snapshot)/2])
+			},
+			codec:   codec,
+			wantErr: ErrInvalidSnapshot,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			restoredCache := NewCache(WithClock(clock))
+			err := restoredCache.LoadSnapshot(data.reader(), data.codec)
+
+			assert.ErrorIs(test, err, data.wantErr)
+			assert.Equal(test, 0, restoredCache.Len())
+		})
+	}
+}
+
+func TestCache_LoadSnapshot_withReaderError(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	err := cache.LoadSnapshot(
+		iotest.ErrReader(iotest.ErrTimeout),
+		SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}},
+	)
+
+	assert.Equal(test, iotest.ErrTimeout, err)
+}
+
+func TestCache_LoadSnapshot_withCodecError(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	cache.Set(IntKey(23), "data", 0)
+
+	var snapshot bytes.Buffer
+	err := cache.SaveSnapshot(
+		&snapshot,
+		SnapshotCodec{Key: NewJSONCodec(nil), Data: NewJSONCodec(nil)},
+	)
+	require.NoError(test, err)
+
+	restoredCache := NewCache(WithClock(clock))
+	err = restoredCache.LoadSnapshot(
+		&snapshot,
+		SnapshotCodec{Key: NewJSONCodec(nil), Data: NewJSONCodec(nil)},
+	)
+
+	assert.EqualError(
+		test,
+		err,
+		"unable to decode the key: float64 doesn't implement hashmap.Key",
+	)
+	assert.Equal(test, 0, restoredCache.Len())
+}
+
+func TestCache_snapshot_withNamespace(test *testing.T) {
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	cache := NewCache(WithClock(clock))
+	cache.Set(IntKey(23), "cache", 0)
+	cache.Namespace("orders").Set(IntKey(42), "orders", 0)
+
+	var snapshot bytes.Buffer
+	err := cache.Namespace("orders").SaveSnapshot(&snapshot, codec)
+	require.NoError(test, err)
+
+	restoredCache := NewCache(WithClock(clock))
+	err = restoredCache.Namespace("orders").LoadSnapshot(&snapshot, codec)
+	require.NoError(test, err)
+
+	assert.Empty(test, iteratedIntKeys(restoredCache))
+	assert.Equal(test, []int{42}, iteratedIntKeys(restoredCache.Namespace("orders")))
+
+	assert.Equal(test, 1, countStoredValues(restoredCache.storage))
+}
+
+func TestCache_snapshot_withNamespaces(test *testing.T) {
+	codec := SnapshotCodec{Key: GobCodec{}, Data: GobCodec{}}
+
+	cache := NewCache(WithClock(clock))
+	cache.Set(IntKey(23), "cache", 0)
+	cache.Namespace("orders").Set(IntKey(23), "orders", 0)
+	cache.Namespace("orders").Namespace("archive").Set(IntKey(42), "archive", 0)
+	cache.Namespace("products").Set(IntKey(12), "products", 0)
+	cache.Namespace("products").Clear()
+
+	var snapshot bytes.Buffer
+	err := cache.SaveSnapshot(&snapshot, codec)
+	require.NoError(test, err)
+
+	restoredCache := NewCache(WithClock(clock))
+	err = restoredCache.LoadSnapshot(&snapshot, codec)
+	require.NoError(test, err)
+
+	assert.Equal(test, []int{23}, iteratedIntKeys(restoredCache))
+
+	restoredOrders := restoredCache.Namespace("orders")
+	gotData, err := restoredOrders.Get(IntKey(23))
+	assert.Equal(test, "orders", gotData)
+	assert.NoError(test, err)
+
+	assert.Equal(
+		test,
+		[]int{42},
+		iteratedIntKeys(restoredOrders.Namespace("archive")),
+	)
+	assert.Empty(test, iteratedIntKeys(restoredCache.Namespace("products")))
+	assert.Equal(test, 3, countStoredValues(restoredCache.storage))
+}
+
+var errWriting = errors.New("writing failed")
+
+// it fails on the first writing if its error is set
+type failingWriter struct {
+	buffer bytes.Buffer
+	err    error
+}
+
+func (writer *failingWriter) Write(data []byte) (int, error) {
+	if writer.err != nil {
+		return 0, writer.err
+	}
+
+	return writer.buffer.Write(data)
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// ErrKeyStale ...
+//
+// It's returned together with data of a stale value, i.e., a value
+// whose usual time to live expired, but whose stale time to live
+// hasn't yet (see the ValueWithStaleTTL() option). So the data is still
+// usable, unlike with other errors.
+//
+var ErrKeyStale = errors.New("key stale")
+
+// the revalidation is started once for the value, so a failed one isn't
+// repeated, and the stale value is served until its expiration
+func (cache Cache) startRevalidation(
+	key hashmap.Key,
+	value models.Value,
+	revalidator Loader,
+) {
+	if revalidator == nil || !value.Revalidation.CompareAndSwap(false, true) {
+		return
+	}
+
+	// concurrent loadings of the same key share the revalidation
+	go cache.loads.do( // nolint: errcheck
+		context.Background(),
+		key,
+		func(ctx context.Context) (interface{}, error) {
+			return cache.revalidate(ctx, key, value, revalidator)
+		},
+	)
+}
+
+func (cache Cache) revalidate(
+	ctx context.Context,
+	key hashmap.Key,
+	staleValue models.Value,
+	revalidator Loader,
+) (interface{}, error) {
+	startTime := cache.clock()
+	data, ttl, err := revalidator(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// the new value keeps the metadata of the stale one, except for the cost,
+	// which is calculated anew if the weigher is set
+	options := []ValueOption{
+		ValueWithIdleTTL(staleValue.IdleTimeout),
+		ValueWithTags(staleValue.Tags...),
+		ValueWithRecomputeCost(cache.clock().Sub(startTime)),
+		ValueWithStaleTTL(staleValue.StaleTTL()),
+	}
+	if cache.weigher == nil {
+		options = append(options, ValueWithCost(staleValue.Cost))
+	}
+
+	value := cache.newValue(key, data, ttl, options)
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		if !transaction.isPresent {
+			return
+		}
+
+		// the stale value could be replaced or cleared during the revalidation
+		currentValue := transaction.data.(models.Value)
+		if cache.isCleared(currentValue) ||
+			currentValue.Revalidation != staleValue.Revalidation {
+			return
+		}
+
+		transaction.set(value)
+	})
+
+	return data, nil
+}
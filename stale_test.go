@@ -0,0 +1,427 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+var errRevalidation = errors.New("revalidation failed")
+
+func TestCache_withStaleTTL(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		options  []Option
+		prepare  func(cache Cache)
+		elapsed  time.Duration
+		wantData interface{}
+		wantErr  error
+		wantTTL  time.Duration
+	}{
+		{
+			name:    "fresh value",
+			options: nil,
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithStaleTTL(time.Minute),
+				)
+			},
+			elapsed:  30 * time.Second,
+			wantData: "data",
+			wantErr:  nil,
+			wantTTL:  90 * time.Second,
+		},
+		{
+			name:    "stale value",
+			options: nil,
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithStaleTTL(time.Minute),
+				)
+			},
+			elapsed:  90 * time.Second,
+			wantData: "data",
+			wantErr:  ErrKeyStale,
+			wantTTL:  30 * time.Second,
+		},
+		{
+			name:    "expired value",
+			options: nil,
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithStaleTTL(time.Minute),
+				)
+			},
+			elapsed:  150 * time.Second,
+			wantData: nil,
+			wantErr:  ErrKeyExpired,
+			wantTTL:  0,
+		},
+		{
+			name:    "stale value with the default stale time to live",
+			options: []Option{WithStaleTTL(time.Minute)},
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Minute)
+			},
+			elapsed:  90 * time.Second,
+			wantData: "data",
+			wantErr:  ErrKeyStale,
+			wantTTL:  30 * time.Second,
+		},
+		{
+			name:    "value with the disabled default stale time to live",
+			options: []Option{WithStaleTTL(time.Minute)},
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithStaleTTL(0),
+				)
+			},
+			elapsed:  90 * time.Second,
+			wantData: nil,
+			wantErr:  ErrKeyExpired,
+			wantTTL:  0,
+		},
+		{
+			name:    "value without a time to live",
+			options: []Option{WithStaleTTL(time.Minute)},
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			elapsed:  90 * time.Second,
+			wantData: "data",
+			wantErr:  nil,
+			wantTTL:  NoExpiration,
+		},
+		{
+			name:    "value with the updated time to live",
+			options: []Option{WithStaleTTL(time.Minute)},
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Minute)
+				cache.Expire(IntKey(23), 80*time.Second) // nolint: errcheck
+			},
+			elapsed:  90 * time.Second,
+			wantData: nil,
+			wantErr:  ErrKeyExpired,
+			wantTTL:  0,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			clock := newSafeClock()
+			options := append([]Option{WithClock(clock.now)}, data.options...)
+			cache := NewCache(options...)
+			data.prepare(cache)
+			clock.add(data.elapsed)
+
+			gotData, gotErr := cache.Get(IntKey(23))
+			gotTTL, _ := cache.TTL(IntKey(23))
+			gotResults := cache.GetMany([]hashmap.Key{IntKey(23)})
+
+			assert.Equal(test, data.wantData, gotData)
+			assert.Equal(test, data.wantErr, gotErr)
+			assert.Equal(test, data.wantTTL, gotTTL)
+			assert.Equal(
+				test,
+				[]BatchResult{{Data: data.wantData, Err: data.wantErr}},
+				gotResults,
+			)
+		})
+	}
+}
+
+func TestCache_Get_withRevalidator(test *testing.T) {
+	for _, data := range []struct {
+		name              string
+		revalidationErr   error
+		wantDataAfterward interface{}
+		wantErrAfterward  error
+	}{
+		{
+			name:              "success",
+			revalidationErr:   nil,
+			wantDataAfterward: "new data",
+			wantErrAfterward:  nil,
+		},
+		{
+			name:              "error",
+			revalidationErr:   errRevalidation,
+			wantDataAfterward: "data",
+			wantErrAfterward:  ErrKeyStale,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			release := make(chan struct{})
+			revalidationErr := data.revalidationErr
+			var revalidatorCallCount atomic.Int64
+			revalidator := func(ctx context.Context, key hashmap.Key) (
+				data interface{},
+				ttl time.Duration,
+				err error,
+			) {
+				revalidatorCallCount.Add(1)
+				<-release
+
+				return "new data", time.Minute, revalidationErr
+			}
+
+			clock := newSafeClock()
+			cache := NewCache(
+				WithClock(clock.now),
+				WithStaleTTL(time.Minute),
+				WithRevalidator(revalidator),
+			)
+			cache.Set(IntKey(23), "data", time.Minute)
+			clock.add(90 * time.Second)
+
+			// the revalidation is started once and doesn't block getting
+			for i := 0; i < 2; i++ {
+				gotData, gotErr := cache.Get(IntKey(23))
+
+				assert.Equal(test, "data", gotData)
+				assert.Equal(test, ErrKeyStale, gotErr)
+			}
+			close(release)
+			require.Eventually(test, func() bool {
+				return revalidatorCallCount.Load() == 1 && !isLoading(cache)
+			}, time.Second, time.Millisecond)
+
+			gotData, gotErr := cache.Get(IntKey(23))
+
+			assert.Equal(test, data.wantDataAfterward, gotData)
+			assert.Equal(test, data.wantErrAfterward, gotErr)
+			assert.Equal(test, int64(1), revalidatorCallCount.Load())
+			assert.Equal(test, int64(3), cache.Stats().Hits+cache.Stats().StaleHits)
+		})
+	}
+}
+
+func TestCache_GetMany_withRevalidator(test *testing.T) {
+	var revalidatorCallCount atomic.Int64
+	clock := newSafeClock()
+	cache := NewCache(
+		WithClock(clock.now),
+		WithStaleTTL(time.Minute),
+		WithRevalidator(func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			revalidatorCallCount.Add(1)
+			clock.add(10 * time.Second)
+
+			return "new data", time.Minute, nil
+		}),
+	)
+	cache.Set(IntKey(23), "data", time.Minute)
+	clock.add(90 * time.Second)
+
+	gotResults := cache.GetMany([]hashmap.Key{IntKey(23)})
+	require.Eventually(test, func() bool {
+		return revalidatorCallCount.Load() == 1 && !isLoading(cache)
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(test, []BatchResult{{Data: "data", Err: ErrKeyStale}}, gotResults)
+
+	// the revalidation uses the actual clock instead of the batch one
+	gotValue, _ := cache.storage.Get(IntKey(23))
+	assert.Equal(test, "new data", gotValue.(models.Value).Data)
+	assert.Equal(test, 10*time.Second, gotValue.(models.Value).RecomputeCost)
+
+	gotTTL, _ := cache.TTL(IntKey(23))
+	assert.Equal(test, 2*time.Minute, gotTTL)
+}
+
+func TestCache_Get_withRevalidatorAndMetadata(test *testing.T) {
+	var revalidatorCallCount atomic.Int64
+	clock := newSafeClock()
+	cache := NewCache(
+		WithClock(clock.now),
+		WithStaleTTL(time.Minute),
+		WithRevalidator(func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			revalidatorCallCount.Add(1)
+			return "new data", time.Minute, nil
+		}),
+	)
+	cache.SetWithOptions(
+		IntKey(23),
+		"data",
+		time.Minute,
+		ValueWithCost(5),
+		ValueWithIdleTTL(time.Hour),
+		ValueWithTags("product:23"),
+	)
+	clock.add(90 * time.Second)
+
+	cache.Get(IntKey(23)) // nolint: errcheck
+	require.Eventually(test, func() bool {
+		return revalidatorCallCount.Load() == 1 && !isLoading(cache)
+	}, time.Second, time.Millisecond)
+
+	gotValue, _ := cache.storage.Get(IntKey(23))
+	assert.Equal(test, "new data", gotValue.(models.Value).Data)
+	assert.Equal(test, int64(5), gotValue.(models.Value).Cost)
+	assert.Equal(test, time.Hour, gotValue.(models.Value).IdleTimeout)
+	assert.Equal(test, int64(5), cache.Cost())
+
+	// the tags survive the revalidation
+	assert.Equal(test, 1, cache.InvalidateTag("product:23"))
+	assert.Equal(test, 0, cache.Len())
+}
+
+func TestCache_Get_withRevalidatorAndFailure(test *testing.T) {
+	var revalidatorCallCount atomic.Int64
+	clock := newSafeClock()
+	cache := NewCache(
+		WithClock(clock.now),
+		WithStaleTTL(time.Minute),
+		WithRevalidator(func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			revalidatorCallCount.Add(1)
+			return nil, 0, errRevalidation
+		}),
+	)
+	cache.Set(IntKey(23), "data", time.Minute)
+
+	// the stale value is served until its expiration without repeating
+	// of the failed revalidation
+	for _, elapsed := range []time.Duration{70 * time.Second, 40 * time.Second} {
+		clock.add(elapsed)
+
+		gotData, gotErr := cache.Get(IntKey(23))
+		require.Eventually(test, func() bool {
+			return revalidatorCallCount.Load() == 1 && !isLoading(cache)
+		}, time.Second, time.Millisecond)
+
+		assert.Equal(test, "data", gotData)
+		assert.Equal(test, ErrKeyStale, gotErr)
+	}
+
+	clock.add(20 * time.Second)
+	gotData, gotErr := cache.Get(IntKey(23))
+
+	assert.Nil(test, gotData)
+	assert.Equal(test, ErrKeyExpired, gotErr)
+	assert.Equal(test, int64(1), revalidatorCallCount.Load())
+}
+
+func TestCache_GetOrLoad_withStaleValue(test *testing.T) {
+	var loaderCallCount atomic.Int64
+	loader := func(ctx context.Context, key hashmap.Key) (
+		data interface{},
+		ttl time.Duration,
+		err error,
+	) {
+		return fmt.Sprintf("data #%d", loaderCallCount.Add(1)), time.Minute, nil
+	}
+
+	clock := newSafeClock()
+	cache := NewCache(WithClock(clock.now), WithStaleTTL(time.Minute))
+	cache.GetOrLoad(context.Background(), IntKey(23), loader) // nolint: errcheck
+	clock.add(90 * time.Second)
+
+	gotData, gotErr := cache.GetOrLoad(context.Background(), IntKey(23), loader)
+	require.Eventually(test, func() bool {
+		return loaderCallCount.Load() == 2 && !isLoading(cache)
+	}, time.Second, time.Millisecond)
+
+	assert.Equal(test, "data #1", gotData)
+	assert.Equal(test, ErrKeyStale, gotErr)
+
+	gotData, gotErr = cache.GetOrLoad(context.Background(), IntKey(23), loader)
+
+	assert.Equal(test, "data #2", gotData)
+	assert.NoError(test, gotErr)
+	assert.Equal(test, int64(2), loaderCallCount.Load())
+
+	// the revalidated value keeps the stale time to live
+	gotTTL, _ := cache.TTL(IntKey(23))
+	assert.Equal(test, 2*time.Minute, gotTTL)
+}
+
+func TestCache_Get_withRevalidatorAndReplacedValue(test *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	clock := newSafeClock()
+	cache := NewCache(
+		WithClock(clock.now),
+		WithStaleTTL(time.Minute),
+		WithRevalidator(func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			close(started)
+			<-release
+
+			return "new data", time.Minute, nil
+		}),
+	)
+	cache.Set(IntKey(23), "data", time.Minute)
+	clock.add(90 * time.Second)
+
+	cache.Get(IntKey(23)) // nolint: errcheck
+	<-started
+	cache.Set(IntKey(23), "other data", 0)
+	close(release)
+	require.Eventually(test, func() bool {
+		return !isLoading(cache)
+	}, time.Second, time.Millisecond)
+
+	gotData, gotErr := cache.Get(IntKey(23))
+
+	assert.Equal(test, "other data", gotData)
+	assert.NoError(test, gotErr)
+}
+
+// it's safe for concurrent access, unlike a captured variable, so it can be
+// used with background revalidation
+type safeClock struct {
+	unixNanoseconds atomic.Int64
+}
+
+func newSafeClock() *safeClock {
+	var clockInstance safeClock
+	clockInstance.unixNanoseconds.Store(clock().UnixNano())
+
+	return &clockInstance
+}
+
+func (clock *safeClock) now() time.Time {
+	return time.Unix(0, clock.unixNanoseconds.Load()).UTC()
+}
+
+func (clock *safeClock) add(duration time.Duration) {
+	clock.unixNanoseconds.Add(int64(duration))
+}
+
+func isLoading(cache Cache) bool {
+	cache.loads.lock.Lock()
+	defer cache.loads.lock.Unlock()
+
+	return len(cache.loads.calls) != 0
+}
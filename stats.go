@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"errors"
+)
+
+// Stats ...
+type Stats struct {
+	Hits          int64
+	NegativeHits  int64 // hits of negative values
+	StaleHits     int64 // hits of stale values
+	Misses        int64 // misses because of the ErrKeyMissed error
+	ExpiredMisses int64 // misses because of the ErrKeyExpired error
+	Sets          int64
+	Deletes       int64 // explicit deletions of present values
+	GCDeletes     int64 // deletions of expired values by garbage collection
+	Evictions     int64
+}
+
+// HitRatio ...
+//
+// It returns a ratio of hits, including ones of negative and stale values,
+// to all getting attempts or zero if there were no attempts.
+//
+func (stats Stats) HitRatio() float64 {
+	hits := stats.Hits + stats.NegativeHits + stats.StaleHits
+	total := hits + stats.Misses + stats.ExpiredMisses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(hits) / float64(total)
+}
+
+// Sub ...
+//
+// It returns a difference between the statistics and the previous ones,
+// i.e., statistics for a period between two snapshots.
+//
+func (stats Stats) Sub(previousStats Stats) Stats {
+	return Stats{
+		Hits:          stats.Hits - previousStats.Hits,
+		NegativeHits:  stats.NegativeHits - previousStats.NegativeHits,
+		StaleHits:     stats.StaleHits - previousStats.StaleHits,
+		Misses:        stats.Misses - previousStats.Misses,
+		ExpiredMisses: stats.ExpiredMisses - previousStats.ExpiredMisses,
+		Sets:          stats.Sets - previousStats.Sets,
+		Deletes:       stats.Deletes - previousStats.Deletes,
+		GCDeletes:     stats.GCDeletes - previousStats.GCDeletes,
+		Evictions:     stats.Evictions - previousStats.Evictions,
+	}
+}
+
+type statsCounters struct {
+	hits          stripedCounter
+	negativeHits  stripedCounter
+	staleHits     stripedCounter
+	misses        stripedCounter
+	expiredMisses stripedCounter
+	sets          stripedCounter
+	deletes       stripedCounter
+	gcDeletes     stripedCounter
+	evictions     stripedCounter
+}
+
+func newStatsCounters() *statsCounters {
+	return new(statsCounters)
+}
+
+func (counters *statsCounters) snapshot() Stats {
+	return Stats{
+		Hits:          counters.hits.load(),
+		NegativeHits:  counters.negativeHits.load(),
+		StaleHits:     counters.staleHits.load(),
+		Misses:        counters.misses.load(),
+		ExpiredMisses: counters.expiredMisses.load(),
+		Sets:          counters.sets.load(),
+		Deletes:       counters.deletes.load(),
+		GCDeletes:     counters.gcDeletes.load(),
+		Evictions:     counters.evictions.load(),
+	}
+}
+
+func (counters *statsCounters) reset() {
+	for _, counter := range []*stripedCounter{
+		&counters.hits,
+		&counters.negativeHits,
+		&counters.staleHits,
+		&counters.misses,
+		&counters.expiredMisses,
+		&counters.sets,
+		&counters.deletes,
+		&counters.gcDeletes,
+		&counters.evictions,
+	} {
+		counter.reset()
+	}
+}
+
+func (counters *statsCounters) addGetting(err error) {
+	switch err {
+	case nil:
+		counters.hits.add(1)
+	case ErrKeyMissed:
+		counters.misses.add(1)
+	case ErrKeyExpired:
+		counters.expiredMisses.add(1)
+	case ErrKeyStale:
+		counters.staleHits.add(1)
+	default:
+		if errors.Is(err, ErrKeyNegative) {
+			counters.negativeHits.add(1)
+		}
+	}
+}
+
+func (counters *statsCounters) addRemoval(reason RemovalReason) {
+	switch reason {
+	case RemovalReasonDeleted:
+		counters.deletes.add(1)
+	case RemovalReasonExpired:
+		counters.gcDeletes.add(1)
+	case RemovalReasonEvicted:
+		counters.evictions.add(1)
+	}
+}
+
+// Stats ...
+//
+// It returns a snapshot of statistics. The snapshot isn't atomic
+// with respect to concurrent operations, but each counter is exact.
+//
+// Getting is counted by the Get(), GetWithGC(), GetOrLoad() and GetMany()
+// methods; setting is counted for each stored value, including ones
+// of conditional setting and incrementing.
+//
+func (cache Cache) Stats() Stats {
+	return cache.stats.snapshot()
+}
+
+// ResetStats ...
+//
+// It sets all the counters to zero. Operations concurrent with resetting
+// may be counted or not. Use the Stats.Sub() method to get deltas
+// between snapshots without resetting.
+//
+func (cache Cache) ResetStats() {
+	cache.stats.reset()
+}
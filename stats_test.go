@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestStats_HitRatio(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		stats Stats
+		want  float64
+	}{
+		{
+			name:  "without getting",
+			stats: Stats{Sets: 23},
+			want:  0,
+		},
+		{
+			name:  "with getting",
+			stats: Stats{Hits: 6, Misses: 3, ExpiredMisses: 1},
+			want:  0.6,
+		},
+		{
+			name:  "with getting of negative values",
+			stats: Stats{Hits: 4, NegativeHits: 2, Misses: 3, ExpiredMisses: 1},
+			want:  0.6,
+		},
+		{
+			name:  "with getting of stale values",
+			stats: Stats{Hits: 4, StaleHits: 2, Misses: 3, ExpiredMisses: 1},
+			want:  0.6,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.stats.HitRatio()
+
+			assert.InDelta(test, data.want, got, 1e-9)
+		})
+	}
+}
+
+func TestStats_Sub(test *testing.T) {
+	stats := Stats{
+		Hits:          10,
+		NegativeHits:  9,
+		StaleHits:     8,
+		Misses:        9,
+		ExpiredMisses: 8,
+		Sets:          7,
+		Deletes:       6,
+		GCDeletes:     5,
+		Evictions:     4,
+	}
+	previousStats := Stats{
+		Hits:          1,
+		NegativeHits:  4,
+		StaleHits:     6,
+		Misses:        2,
+		ExpiredMisses: 3,
+		Sets:          4,
+		Deletes:       3,
+		GCDeletes:     2,
+		Evictions:     1,
+	}
+	got := stats.Sub(previousStats)
+
+	want := Stats{
+		Hits:          9,
+		NegativeHits:  5,
+		StaleHits:     2,
+		Misses:        7,
+		ExpiredMisses: 5,
+		Sets:          3,
+		Deletes:       3,
+		GCDeletes:     3,
+		Evictions:     3,
+	}
+	assert.Equal(test, want, got)
+}
+
+func TestCache_Stats(test *testing.T) {
+	cache := NewCache(WithClock(clock), WithMaxSize(3))
+	cache.Set(IntKey(1), "one", 0)
+	cache.Set(IntKey(2), "two", -time.Second)
+	cache.SetMany(
+		[]Entry{{Key: IntKey(3), Data: "three"}, {Key: IntKey(4), Data: "four"}},
+		0,
+	)
+	cache.Get(IntKey(4))       // nolint: errcheck
+	cache.Get(IntKey(23))      // nolint: errcheck
+	cache.GetWithGC(IntKey(2)) // nolint: errcheck
+	cache.GetMany([]hashmap.Key{IntKey(3), IntKey(4)})
+	cache.Delete(IntKey(3))
+	cache.Delete(IntKey(3))
+	cache.GetOrLoad( // nolint: errcheck
+		context.Background(),
+		IntKey(5),
+		func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			return "five", 0, nil
+		},
+	)
+
+	want := Stats{
+		Hits:          3,
+		Misses:        2,
+		ExpiredMisses: 1,
+		Sets:          5,
+		Deletes:       1,
+		GCDeletes:     1,
+		Evictions:     1,
+	}
+	assert.Equal(test, want, cache.Stats())
+
+	cache.ResetStats()
+
+	assert.Equal(test, Stats{}, cache.Stats())
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+const counterStripeCount = 32
+
+// it occupies a whole cache line to prevent false sharing
+type paddedCounter struct {
+	value atomic.Int64
+	_     [56]byte
+}
+
+// it's a counter with low contention on increments at the expense
+// of slower loading; an increment goes to a random stripe, so concurrent
+// increments rarely meet on the same one
+type stripedCounter struct {
+	stripes [counterStripeCount]paddedCounter
+}
+
+func (counter *stripedCounter) add(delta int64) {
+	counter.stripes[rand.Uint32()%counterStripeCount].value.Add(delta)
+}
+
+func (counter *stripedCounter) load() int64 {
+	var sum int64
+	for index := range counter.stripes {
+		sum += counter.stripes[index].value.Load()
+	}
+
+	return sum
+}
+
+func (counter *stripedCounter) reset() {
+	for index := range counter.stripes {
+		counter.stripes[index].value.Store(0)
+	}
+}
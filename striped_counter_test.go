@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_stripedCounter(test *testing.T) {
+	const (
+		goroutineCount = 10
+		addingCount    = 1000
+	)
+
+	var counter stripedCounter
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < goroutineCount; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for j := 0; j < addingCount; j++ {
+				counter.add(2)
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, int64(2*goroutineCount*addingCount), counter.load())
+
+	counter.reset()
+
+	assert.Equal(test, int64(0), counter.load())
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type subscription struct {
+	filter         EventFilter
+	overflowPolicy OverflowPolicy
+
+	// it serializes sending of events by concurrent publishers
+	lock   sync.Mutex
+	events chan Event
+	done   chan struct{}
+}
+
+func (subscription *subscription) send(event Event) {
+	if !subscription.filter.match(event) {
+		return
+	}
+
+	subscription.lock.Lock()
+	defer subscription.lock.Unlock()
+
+	switch subscription.overflowPolicy {
+	case OverflowPolicyDropOldest:
+		for {
+			select {
+			case subscription.events <- event:
+				return
+			default:
+			}
+
+			select {
+			case <-subscription.events:
+			default:
+			}
+		}
+	case OverflowPolicyBlock:
+		select {
+		case subscription.events <- event:
+		case <-subscription.done:
+		}
+	default:
+		select {
+		case subscription.events <- event:
+		default:
+		}
+	}
+}
+
+type eventHub struct {
+	subscriptionCount atomic.Int64
+
+	lock          sync.RWMutex
+	subscriptions map[*subscription]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscriptions: make(map[*subscription]struct{})}
+}
+
+func (hub *eventHub) hasSubscriptions() bool {
+	return hub.subscriptionCount.Load() != 0
+}
+
+func (hub *eventHub) subscribe(subscription *subscription) {
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	hub.subscriptions[subscription] = struct{}{}
+	hub.subscriptionCount.Add(1)
+}
+
+func (hub *eventHub) unsubscribe(subscription *subscription) {
+	// unblock a publisher, if any, before waiting for it
+	close(subscription.done)
+
+	hub.lock.Lock()
+	defer hub.lock.Unlock()
+
+	delete(hub.subscriptions, subscription)
+	hub.subscriptionCount.Add(-1)
+
+	// nobody sends to the channel anymore
+	close(subscription.events)
+}
+
+func (hub *eventHub) publish(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	hub.lock.RLock()
+	defer hub.lock.RUnlock()
+
+	for subscription := range hub.subscriptions {
+		for _, event := range events {
+			subscription.send(event)
+		}
+	}
+}
+
+// Subscribe ...
+//
+// It returns a channel of events matching the filter. The channel is closed
+// after canceling of the subscription. The cancel function is idempotent.
+//
+// Events are sent after an operation releases a lock of the key,
+// so events of concurrent operations can come in a different order.
+// Events of garbage collection are sent, too.
+//
+func (cache Cache) Subscribe(
+	filter EventFilter,
+	options ...SubscriptionOption,
+) (events <-chan Event, cancel func()) {
+	// default config
+	config := subscriptionConfig{
+		bufferSize:     1024,
+		overflowPolicy: OverflowPolicyDropNewest,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	if config.bufferSize < 1 {
+		config.bufferSize = 1
+	}
+
+	subscription := &subscription{
+		filter:         filter,
+		overflowPolicy: config.overflowPolicy,
+
+		events: make(chan Event, config.bufferSize),
+		done:   make(chan struct{}),
+	}
+	cache.events.subscribe(subscription)
+
+	var once sync.Once
+	return subscription.events, func() {
+		once.Do(func() { cache.events.unsubscribe(subscription) })
+	}
+}
@@ -0,0 +1,51 @@
+package cache
+
+// OverflowPolicy ...
+//
+// It defines handling of an event when a buffer of a subscriber is full.
+//
+type OverflowPolicy int
+
+// ...
+const (
+	// OverflowPolicyDropNewest means dropping of the new event
+	OverflowPolicyDropNewest OverflowPolicy = iota
+	// OverflowPolicyDropOldest means dropping of the oldest buffered event
+	// in favor of the new one
+	OverflowPolicyDropOldest
+	// OverflowPolicyBlock means blocking of the operation that produced
+	// the event until the subscriber reads it or cancels the subscription
+	OverflowPolicyBlock
+)
+
+type subscriptionConfig struct {
+	bufferSize     int
+	overflowPolicy OverflowPolicy
+}
+
+// SubscriptionOption ...
+type SubscriptionOption func(config *subscriptionConfig)
+
+// SubscriptionWithBufferSize ...
+//
+// It should be positive; otherwise, it's considered equal to one.
+//
+// Default: 1024.
+//
+func SubscriptionWithBufferSize(bufferSize int) SubscriptionOption {
+	return func(config *subscriptionConfig) {
+		config.bufferSize = bufferSize
+	}
+}
+
+// SubscriptionWithOverflowPolicy ...
+//
+// Default: OverflowPolicyDropNewest.
+//
+func SubscriptionWithOverflowPolicy(
+	overflowPolicy OverflowPolicy,
+) SubscriptionOption {
+	return func(config *subscriptionConfig) {
+		config.overflowPolicy = overflowPolicy
+	}
+}
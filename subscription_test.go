@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_Subscribe(test *testing.T) {
+	for _, data := range []struct {
+		name       string
+		options    []Option
+		filter     EventFilter
+		update     func(cache Cache)
+		wantEvents []Event
+	}{
+		{
+			name:   "with setting",
+			filter: EventFilter{},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+				cache.Set(IntKey(23), "data #2", 0)
+			},
+			wantEvents: []Event{
+				{Type: EventTypeSet, Key: IntKey(23), Data: "data #1", Time: clock()},
+				{Type: EventTypeSet, Key: IntKey(23), Data: "data #2", Time: clock()},
+			},
+		},
+		{
+			name:   "with setting over an expired value",
+			filter: EventFilter{},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", -time.Second)
+				cache.Set(IntKey(23), "data #2", 0)
+			},
+			wantEvents: []Event{
+				{Type: EventTypeSet, Key: IntKey(23), Data: "data #1", Time: clock()},
+				{Type: EventTypeExpire, Key: IntKey(23), Data: "data #1", Time: clock()},
+				{Type: EventTypeSet, Key: IntKey(23), Data: "data #2", Time: clock()},
+			},
+		},
+		{
+			name:   "with deletion",
+			filter: EventFilter{Types: EventTypeDelete},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+				cache.Delete(IntKey(23))
+				cache.Set(IntKey(42), "data #2", 0)
+				cache.Clear()
+			},
+			wantEvents: []Event{
+				{Type: EventTypeDelete, Key: IntKey(23), Data: "data #1", Time: clock()},
+				{Type: EventTypeDelete, Key: IntKey(42), Data: "data #2", Time: clock()},
+			},
+		},
+		{
+			name:    "with eviction",
+			options: []Option{WithMaxSize(1)},
+			filter:  EventFilter{Types: EventTypeEvict},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+				cache.Set(IntKey(42), "data #2", 0)
+			},
+			wantEvents: []Event{
+				{Type: EventTypeEvict, Key: IntKey(23), Data: "data #1", Time: clock()},
+			},
+		},
+		{
+			name: "with a key filter",
+			filter: EventFilter{
+				Key: func(key hashmap.Key) bool { return key == IntKey(42) },
+			},
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data #1", 0)
+				cache.Set(IntKey(42), "data #2", 0)
+			},
+			wantEvents: []Event{
+				{Type: EventTypeSet, Key: IntKey(42), Data: "data #2", Time: clock()},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(append([]Option{WithClock(clock)}, data.options...)...)
+			events, cancel := cache.Subscribe(data.filter)
+			data.update(cache)
+			cancel()
+
+			var gotEvents []Event
+			for event := range events {
+				gotEvents = append(gotEvents, event)
+			}
+
+			assert.Equal(test, data.wantEvents, gotEvents)
+		})
+	}
+}
+
+func TestCache_Subscribe_withOverflowPolicy(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		overflowPolicy OverflowPolicy
+		wantData       []interface{}
+	}{
+		{
+			name:           "dropping of the newest events",
+			overflowPolicy: OverflowPolicyDropNewest,
+			wantData:       []interface{}{0, 1},
+		},
+		{
+			name:           "dropping of the oldest events",
+			overflowPolicy: OverflowPolicyDropOldest,
+			wantData:       []interface{}{3, 4},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			events, cancel := cache.Subscribe(
+				EventFilter{},
+				SubscriptionWithBufferSize(2),
+				SubscriptionWithOverflowPolicy(data.overflowPolicy),
+			)
+			for i := 0; i < 5; i++ {
+				cache.Set(IntKey(i), i, 0)
+			}
+			cancel()
+
+			var gotData []interface{}
+			for event := range events {
+				gotData = append(gotData, event.Data)
+			}
+
+			assert.Equal(test, data.wantData, gotData)
+		})
+	}
+}
+
+func TestCache_Subscribe_withBlocking(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	events, cancel := cache.Subscribe(
+		EventFilter{},
+		SubscriptionWithBufferSize(1),
+		SubscriptionWithOverflowPolicy(OverflowPolicyBlock),
+	)
+
+	isSet := make(chan struct{})
+	go func() {
+		defer close(isSet)
+
+		for i := 0; i < 3; i++ {
+			cache.Set(IntKey(i), i, 0)
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		event := <-events
+		assert.Equal(test, i, event.Data)
+	}
+	<-isSet
+
+	// canceling should unblock a publisher
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	cache.Set(IntKey(3), 3, 0)
+	cache.Set(IntKey(4), 4, 0)
+	cancel()
+}
+
+func TestCache_Subscribe_withGC(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := NewCacheWithGC(
+		ctx,
+		WithGCAndClock(clock),
+		WithGCAndGCFactory(func(storage hashmap.Storage, clock models.Clock) gc.GC {
+			return gc.NewTotalGC(storage, gc.TotalGCWithClock(clock))
+		}),
+		WithGCAndGCPeriod(time.Millisecond),
+	)
+	events, cancelSubscription := cache.Subscribe(
+		EventFilter{Types: EventTypeExpire},
+	)
+	defer cancelSubscription()
+
+	cache.Set(IntKey(23), "data", -time.Second)
+
+	select {
+	case event := <-events:
+		wantEvent := Event{
+			Type: EventTypeExpire,
+			Key:  IntKey(23),
+			Data: "data",
+			Time: clock(),
+		}
+		assert.Equal(test, wantEvent, event)
+	case <-time.After(time.Second):
+		assert.Fail(test, "the expire event wasn't got")
+	}
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"sync"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// InvalidateTag ...
+//
+// It deletes all values with the tag and returns a count of deleted values
+// excluding expired ones. It takes time proportional to a count of values
+// with the tag, not to a size of the cache.
+//
+// Values set with the tag concurrently may be not deleted.
+//
+func (cache Cache) InvalidateTag(tag string) (count int) {
+	for _, key := range cache.tags.keys(tag) {
+		cache.runTransaction(key, func(transaction *keyTransaction) {
+			if !transaction.isPresent {
+				return
+			}
+
+			// the key could be set again without the tag after getting of the keys;
+			// cleared values are deleted by the Clear() method
+			value := transaction.data.(models.Value)
+			if cache.isCleared(value) || !hasTag(value, tag) {
+				return
+			}
+
+			isExpired := value.IsExpired(cache.clock)
+			if transaction.delete(RemovalReasonDeleted) && !isExpired {
+				count++
+			}
+		})
+	}
+
+	return count
+}
+
+func hasTag(value models.Value, tag string) bool {
+	for _, valueTag := range value.Tags {
+		if valueTag == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// it's updated by transactions under a lock of a key, so it's consistent
+// with the storage for each key
+type tagIndex struct {
+	lock      sync.Mutex
+	keysByTag map[string]keySet
+}
+
+func newTagIndex() *tagIndex {
+	return &tagIndex{keysByTag: make(map[string]keySet)}
+}
+
+func (index *tagIndex) add(key hashmap.Key, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	for _, tag := range tags {
+		keys, ok := index.keysByTag[tag]
+		if !ok {
+			keys = make(keySet)
+			index.keysByTag[tag] = keys
+		}
+
+		keys.add(key)
+	}
+}
+
+func (index *tagIndex) remove(key hashmap.Key, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	for _, tag := range tags {
+		keys, ok := index.keysByTag[tag]
+		if !ok {
+			continue
+		}
+
+		keys.remove(key)
+		if len(keys) == 0 {
+			delete(index.keysByTag, tag)
+		}
+	}
+}
+
+func (index *tagIndex) keys(tag string) []hashmap.Key {
+	index.lock.Lock()
+	defer index.lock.Unlock()
+
+	return index.keysByTag[tag].slice()
+}
+
+// keys aren't required to be comparable, so they're grouped by their hashes
+type keySet map[int][]hashmap.Key
+
+func (set keySet) add(key hashmap.Key) {
+	hash := key.Hash()
+	for _, otherKey := range set[hash] {
+		if otherKey.Equals(key) {
+			return
+		}
+	}
+
+	set[hash] = append(set[hash], key)
+}
+
+func (set keySet) remove(key hashmap.Key) {
+	hash := key.Hash()
+	keys := set[hash]
+	for index, otherKey := range keys {
+		if !otherKey.Equals(key) {
+			continue
+		}
+
+		if len(keys) == 1 {
+			delete(set, hash)
+			return
+		}
+
+		keys[index] = keys[len(keys)-1]
+		set[hash] = keys[:len(keys)-1]
+		return
+	}
+}
+
+func (set keySet) slice() []hashmap.Key {
+	keys := make([]hashmap.Key, 0, len(set))
+	for _, keysWithHash := range set {
+		keys = append(keys, keysWithHash...)
+	}
+
+	return keys
+}
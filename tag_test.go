@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// all its instances have the same hash
+type collidingKey int
+
+func (key collidingKey) Hash() int {
+	return 23
+}
+
+func (key collidingKey) Equals(other hashmap.Key) bool {
+	otherKey, ok := other.(collidingKey)
+	return ok && key == otherKey
+}
+
+func TestCache_InvalidateTag(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		prepare       func(cache Cache)
+		tag           string
+		wantCount     int
+		wantKeys      []int
+		wantTaggedFor assert.ValueAssertionFunc
+	}{
+		{
+			name: "with an unknown tag",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(IntKey(1), "one", 0, ValueWithTags("product:23"))
+			},
+			tag:           "product:42",
+			wantCount:     0,
+			wantKeys:      []int{1},
+			wantTaggedFor: assert.Empty,
+		},
+		{
+			name: "with values with the tag",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(IntKey(1), "one", 0, ValueWithTags("product:23"))
+				cache.SetWithOptions(
+					IntKey(2),
+					"two",
+					0,
+					ValueWithTags("product:23", "product:42"),
+				)
+				cache.SetWithOptions(IntKey(3), "three", 0, ValueWithTags("product:42"))
+				cache.Set(IntKey(4), "four", 0)
+			},
+			tag:           "product:23",
+			wantCount:     2,
+			wantKeys:      []int{3, 4},
+			wantTaggedFor: assert.Empty,
+		},
+		{
+			name: "with an expired value with the tag",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(IntKey(1), "one", 0, ValueWithTags("product:23"))
+				cache.SetWithOptions(
+					IntKey(2),
+					"two",
+					-time.Second,
+					ValueWithTags("product:23"),
+				)
+			},
+			tag:           "product:23",
+			wantCount:     1,
+			wantKeys:      nil,
+			wantTaggedFor: assert.Empty,
+		},
+		{
+			name: "with a value set again without the tag",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(IntKey(1), "one", 0, ValueWithTags("product:23"))
+				cache.SetWithOptions(IntKey(2), "two", 0, ValueWithTags("product:23"))
+				cache.Set(IntKey(2), "two", 0)
+			},
+			tag:           "product:23",
+			wantCount:     1,
+			wantKeys:      []int{2},
+			wantTaggedFor: assert.Empty,
+		},
+		{
+			name: "with a cleared value with the tag",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(IntKey(1), "one", 0, ValueWithTags("product:23"))
+				cache.generation.Add(1)
+			},
+			tag:           "product:23",
+			wantCount:     0,
+			wantKeys:      nil,
+			wantTaggedFor: assert.NotEmpty,
+		},
+		{
+			name: "with keys with colliding hashes",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(collidingKey(1), "one", 0, ValueWithTags("product:23"))
+				cache.SetWithOptions(collidingKey(2), "two", 0, ValueWithTags("product:23"))
+				cache.SetWithOptions(collidingKey(3), "three", 0)
+			},
+			tag:           "product:23",
+			wantCount:     2,
+			wantKeys:      []int{3},
+			wantTaggedFor: assert.Empty,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			gotCount := cache.InvalidateTag(data.tag)
+
+			var gotKeys []int
+			cache.Iterate(context.Background(), func(key hashmap.Key, data interface{}) bool {
+				switch key := key.(type) {
+				case IntKey:
+					gotKeys = append(gotKeys, int(key))
+				case collidingKey:
+					gotKeys = append(gotKeys, int(key))
+				}
+
+				return true
+			})
+			sort.Ints(gotKeys)
+
+			assert.Equal(test, data.wantCount, gotCount)
+			assert.Equal(test, data.wantKeys, gotKeys)
+			data.wantTaggedFor(test, cache.tags.keys(data.tag))
+		})
+	}
+}
+
+func TestCache_tagIndexCleanup(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		update func(cache Cache)
+	}{
+		{
+			name: "deletion",
+			update: func(cache Cache) {
+				cache.Delete(IntKey(23))
+			},
+		},
+		{
+			name: "replacing without tags",
+			update: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+		},
+		{
+			name: "replacing with other tags",
+			update: func(cache Cache) {
+				cache.SetWithOptions(IntKey(23), "data", 0, ValueWithTags("other"))
+				cache.Delete(IntKey(23))
+			},
+		},
+		{
+			name: "eviction",
+			update: func(cache Cache) {
+				cache.Set(IntKey(42), "data", 0)
+			},
+		},
+		{
+			name: "clearing",
+			update: func(cache Cache) {
+				cache.Clear()
+			},
+		},
+		{
+			name: "garbage collection",
+			update: func(cache Cache) {
+				storage := gcStorage{Storage: cache.storage, cache: cache}
+				currentTime := clock().Add(time.Hour)
+				gc.NewTotalGC(storage, gc.TotalGCWithClock(func() time.Time {
+					return currentTime
+				})).Clean(context.Background())
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			currentTime := clock()
+			cache := NewCache(
+				WithClock(func() time.Time { return currentTime }),
+				WithMaxSize(1),
+			)
+			cache.SetWithOptions(
+				IntKey(23),
+				"data",
+				time.Minute,
+				ValueWithTags("product:23", "page"),
+			)
+			currentTime = currentTime.Add(time.Hour)
+
+			data.update(cache)
+
+			assert.Empty(test, cache.tags.keysByTag)
+		})
+	}
+}
+
+func TestCache_SetMany_withTags(test *testing.T) {
+	cache := NewCache(WithClock(clock))
+	cache.SetMany(
+		[]Entry{{Key: IntKey(1), Data: "one"}, {Key: IntKey(2), Data: "two"}},
+		0,
+		ValueWithTags("product:23"),
+	)
+
+	gotCount := cache.InvalidateTag("product:23")
+
+	assert.Equal(test, 2, gotCount)
+	assert.Equal(test, 0, cache.Len())
+}
+
+func Test_keySet(test *testing.T) {
+	set := make(keySet)
+	set.add(collidingKey(1))
+	set.add(collidingKey(2))
+	set.add(collidingKey(2))
+	set.add(IntKey(3))
+	assert.ElementsMatch(
+		test,
+		[]hashmap.Key{collidingKey(1), collidingKey(2), IntKey(3)},
+		set.slice(),
+	)
+
+	set.remove(collidingKey(1))
+	set.remove(collidingKey(42))
+	assert.ElementsMatch(test, []hashmap.Key{collidingKey(2), IntKey(3)}, set.slice())
+
+	set.remove(collidingKey(2))
+	set.remove(IntKey(3))
+	assert.Empty(test, set)
+}
+
+func Test_hasTag(test *testing.T) {
+	value := models.Value{Data: "data", Tags: []string{"product:23", "page"}}
+
+	assert.True(test, hasTag(value, "page"))
+	assert.False(test, hasTag(value, "product:42"))
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// NoExpiration ...
+//
+// It's returned by the Cache.TTL() method for a value without expiration.
+//
+const NoExpiration time.Duration = -1
+
+// TTL ...
+//
+// It returns a remaining time to live, taking into account expiration
+// on idleness, or NoExpiration.
+//
+// The error can be ErrKeyMissed or ErrKeyExpired only.
+//
+func (cache Cache) TTL(key hashmap.Key) (ttl time.Duration, err error) {
+	data, ok := cache.storage.Get(key)
+	if !ok || cache.isCleared(data.(models.Value)) {
+		return 0, ErrKeyMissed
+	}
+
+	value := data.(models.Value)
+	currentTime := cache.clock()
+	if value.IsExpired(func() time.Time { return currentTime }) {
+		return 0, ErrKeyExpired
+	}
+
+	ttl = NoExpiration
+	if !value.ExpirationTime.IsZero() {
+		ttl = value.ExpirationTime.Sub(currentTime)
+	}
+
+	idleExpirationTime := value.IdleExpirationTime()
+	if !idleExpirationTime.IsZero() {
+		idleTTL := idleExpirationTime.Sub(currentTime)
+		if ttl == NoExpiration || idleTTL < ttl {
+			ttl = idleTTL
+		}
+	}
+
+	return ttl, nil
+}
+
+// Expire ...
+//
+// It sets a new time to live of a present value. Zero time to live means
+// infinite one. The time to live is jittered if the jitter is set
+// (see the WithTTLJitter() option).
+//
+// The error can be ErrKeyMissed or ErrKeyExpired only.
+//
+func (cache Cache) Expire(key hashmap.Key, ttl time.Duration) error {
+	return cache.ExpireAt(key, cache.expirationTime(cache.clock(), ttl))
+}
+
+// ExpireAt ...
+//
+// It sets a new expiration time of a present value. Zero time means infinite
+// time to live. It removes stale serving of the value, if the latter is set.
+//
+// The error can be ErrKeyMissed or ErrKeyExpired only.
+//
+func (cache Cache) ExpireAt(key hashmap.Key, expirationTime time.Time) error {
+	return cache.updateValue(key, func(value *models.Value) {
+		value.ExpirationTime = expirationTime
+		value.StaleTime = time.Time{}
+		value.Revalidation = nil
+	})
+}
+
+// Persist ...
+//
+// It removes both expiration by the time to live and on idleness
+// of a present value, as well as its stale serving.
+//
+// The error can be ErrKeyMissed or ErrKeyExpired only.
+//
+func (cache Cache) Persist(key hashmap.Key) error {
+	return cache.updateValue(key, func(value *models.Value) {
+		value.ExpirationTime = time.Time{}
+		value.StaleTime = time.Time{}
+		value.Revalidation = nil
+		value.IdleTimeout = 0
+		value.AccessTime = nil
+	})
+}
+
+// Touch ...
+//
+// It marks a present value as accessed without getting it,
+// i.e., it postpones its expiration on idleness and notifies
+// the eviction policy.
+//
+// The error can be ErrKeyMissed or ErrKeyExpired only.
+//
+func (cache Cache) Touch(key hashmap.Key) (err error) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		var value models.Value
+		value, err = transaction.entry()
+		if err != nil {
+			return
+		}
+
+		value.Touch(cache.clock)
+		if cache.evictionPolicy != nil {
+			cache.evictionPolicy.OnAccess(key)
+		}
+	})
+
+	return err
+}
+
+func (cache Cache) updateValue(
+	key hashmap.Key,
+	update func(value *models.Value),
+) (err error) {
+	cache.runTransaction(key, func(transaction *keyTransaction) {
+		var value models.Value
+		value, err = transaction.entry()
+		if err != nil {
+			return
+		}
+
+		update(&value)
+		transaction.modify(value)
+	})
+
+	return err
+}
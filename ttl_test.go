@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func TestCache_TTL(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		prepare func(cache Cache)
+		wantTTL time.Duration
+		wantErr error
+	}{
+		{
+			name:    "error with a missed key",
+			prepare: func(cache Cache) {},
+			wantTTL: 0,
+			wantErr: ErrKeyMissed,
+		},
+		{
+			name: "error with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", -time.Second)
+			},
+			wantTTL: 0,
+			wantErr: ErrKeyExpired,
+		},
+		{
+			name: "success without expiration",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			wantTTL: NoExpiration,
+			wantErr: nil,
+		},
+		{
+			name: "success with a TTL",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Second)
+			},
+			wantTTL: time.Second,
+			wantErr: nil,
+		},
+		{
+			name: "success with an idle TTL",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(IntKey(23), "data", 0, ValueWithIdleTTL(time.Second))
+			},
+			wantTTL: time.Second,
+			wantErr: nil,
+		},
+		{
+			name: "success with a TTL less than an idle TTL",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Second,
+					ValueWithIdleTTL(time.Minute),
+				)
+			},
+			wantTTL: time.Second,
+			wantErr: nil,
+		},
+		{
+			name: "success with a TTL greater than an idle TTL",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithIdleTTL(time.Second),
+				)
+			},
+			wantTTL: time.Second,
+			wantErr: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			cache := NewCache(WithClock(clock))
+			data.prepare(cache)
+
+			gotTTL, gotErr := cache.TTL(IntKey(23))
+
+			assert.Equal(test, data.wantTTL, gotTTL)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestCache_updatingOfTTL(test *testing.T) {
+	type args struct {
+		update func(cache Cache, key hashmap.Key) error
+	}
+
+	for _, data := range []struct {
+		name      string
+		prepare   func(cache Cache)
+		args      args
+		wantValue models.Value
+		wantErr   error
+	}{
+		{
+			name:    "Expire/error with a missed key",
+			prepare: func(cache Cache) {},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Expire(key, time.Second)
+				},
+			},
+			wantValue: models.Value{},
+			wantErr:   ErrKeyMissed,
+		},
+		{
+			name: "Expire/error with an expired key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", -time.Second)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Expire(key, time.Second)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(-time.Second),
+			},
+			wantErr: ErrKeyExpired,
+		},
+		{
+			name: "Expire/success with a TTL",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", 0)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Expire(key, time.Second)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(time.Second * 3 / 2),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Expire/success without a TTL",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Second)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Expire(key, 0)
+				},
+			},
+			wantValue: models.Value{Data: "data", ExpirationTime: time.Time{}},
+			wantErr:   nil,
+		},
+		{
+			name: "ExpireAt/success",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(23), "data", time.Second)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.ExpireAt(key, clock().Add(time.Minute))
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(time.Minute),
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Persist/success",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithIdleTTL(time.Second),
+				)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Persist(key)
+				},
+			},
+			wantValue: models.Value{Data: "data", ExpirationTime: time.Time{}},
+			wantErr:   nil,
+		},
+		{
+			name: "Touch/error with a missed key",
+			prepare: func(cache Cache) {
+				cache.Set(IntKey(42), "data", 0)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Touch(key)
+				},
+			},
+			wantValue: models.Value{},
+			wantErr:   ErrKeyMissed,
+		},
+		{
+			name: "Touch/success",
+			prepare: func(cache Cache) {
+				cache.SetWithOptions(
+					IntKey(23),
+					"data",
+					time.Minute,
+					ValueWithIdleTTL(time.Second),
+				)
+			},
+			args: args{
+				update: func(cache Cache, key hashmap.Key) error {
+					return cache.Touch(key)
+				},
+			},
+			wantValue: models.Value{
+				Data:           "data",
+				ExpirationTime: clock().Add(time.Minute),
+				IdleTimeout:    time.Second,
+				AccessTime:     models.NewAccessTime(clock().Add(time.Second / 2)),
+			},
+			wantErr: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			currentTime := clock()
+			cache := NewCache(WithClock(func() time.Time { return currentTime }))
+			data.prepare(cache)
+
+			currentTime = currentTime.Add(time.Second / 2)
+			gotErr := data.args.update(cache, IntKey(23))
+
+			gotValue, _ := cache.storage.Get(IntKey(23))
+			if gotValue == nil {
+				gotValue = models.Value{}
+			}
+
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestCache_Touch_withEvictionPolicy(test *testing.T) {
+	evictionPolicy := new(MockEvictionPolicy)
+	evictionPolicy.On("OnInsert", IntKey(23))
+	evictionPolicy.On("OnAccess", IntKey(23))
+
+	cache := NewCache(
+		WithClock(clock),
+		WithMaxSize(1),
+		WithEvictionPolicy(evictionPolicy),
+	)
+	cache.Set(IntKey(23), "data", 0)
+	err := cache.Touch(IntKey(23))
+
+	mock.AssertExpectationsForObjects(test, evictionPolicy)
+	assert.NoError(test, err)
+}
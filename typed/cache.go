@@ -0,0 +1,165 @@
+package typed
+
+import (
+	"context"
+	"time"
+
+	cache "github.com/thewizardplusplus/go-cache"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+// Handler ...
+//
+// If it returns false, iteration is broken.
+//
+type Handler[K comparable, V any] func(key K, value V) bool
+
+// Loader ...
+//
+// It should return the value for the key and its time to live.
+// Zero time to live means infinite one.
+//
+type Loader[K comparable, V any] func(ctx context.Context, key K) (
+	value V,
+	ttl time.Duration,
+	err error,
+)
+
+// Cache ...
+//
+// It's a type-safe wrapper over the cache.Cache structure. Keys are hashed
+// automatically, so they don't need to implement the hashmap.Key interface.
+//
+type Cache[K comparable, V any] struct {
+	cache cache.Cache
+}
+
+// NewCache ...
+func NewCache[K comparable, V any](options ...cache.Option) Cache[K, V] {
+	return Cache[K, V]{cache: cache.NewCache(options...)}
+}
+
+// NewCacheWithGC ...
+//
+// It additionally runs garbage collection in background.
+//
+func NewCacheWithGC[K comparable, V any](
+	ctx context.Context,
+	options ...cache.OptionWithGC,
+) Cache[K, V] {
+	return Cache[K, V]{cache: cache.NewCacheWithGC(ctx, options...)}
+}
+
+// Untyped ...
+//
+// It returns the underlying instance of the cache.Cache structure.
+//
+func (cache Cache[K, V]) Untyped() cache.Cache {
+	return cache.cache
+}
+
+// Get ...
+//
+// The error can be cache.ErrKeyMissed, cache.ErrKeyExpired, cache.ErrKeyStale
+// (the value is returned for it) or an error of a negative value
+// (see the cache.Cache.SetNegative() method).
+//
+func (cache Cache[K, V]) Get(key K) (value V, err error) {
+	return castData[V](cache.cache.Get(typedKey[K]{key}))
+}
+
+// GetWithGC ...
+//
+// It additionally deletes the value if its time to live expired.
+//
+func (cache Cache[K, V]) GetWithGC(key K) (value V, err error) {
+	return castData[V](cache.cache.GetWithGC(typedKey[K]{key}))
+}
+
+// GetIncludingExpired ...
+//
+// See the cache.Cache.GetIncludingExpired() method for details.
+//
+func (cache Cache[K, V]) GetIncludingExpired(key K) (
+	value V,
+	expirationTime time.Time,
+	err error,
+) {
+	data, expirationTime, err :=
+		cache.cache.GetIncludingExpired(typedKey[K]{key})
+	value, err = castData[V](data, err)
+
+	return value, expirationTime, err
+}
+
+// GetOrLoad ...
+//
+// See the cache.Cache.GetOrLoad() method for details.
+//
+func (cache Cache[K, V]) GetOrLoad(
+	ctx context.Context,
+	key K,
+	loader Loader[K, V],
+) (value V, err error) {
+	return castData[V](cache.cache.GetOrLoad(
+		ctx,
+		typedKey[K]{key},
+		func(ctx context.Context, key hashmap.Key) (
+			data interface{},
+			ttl time.Duration,
+			err error,
+		) {
+			return loader(ctx, key.(typedKey[K]).value)
+		},
+	))
+}
+
+// Iterate ...
+//
+// If the handler returns false, iteration is broken.
+//
+func (cache Cache[K, V]) Iterate(ctx context.Context, handler Handler[K, V]) bool {
+	return cache.cache.Iterate(ctx, castHandler(handler))
+}
+
+// IterateWithGC ...
+//
+// It additionally deletes iterated values if their time to live expired.
+//
+// If the handler returns false, iteration is broken.
+//
+func (cache Cache[K, V]) IterateWithGC(
+	ctx context.Context,
+	handler Handler[K, V],
+) bool {
+	return cache.cache.IterateWithGC(ctx, castHandler(handler))
+}
+
+// Set ...
+//
+// Zero time to live means infinite one.
+//
+func (cache Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	cache.cache.Set(typedKey[K]{key}, value, ttl)
+}
+
+// Delete ...
+func (cache Cache[K, V]) Delete(key K) {
+	cache.cache.Delete(typedKey[K]{key})
+}
+
+// data is nil for all errors except cache.ErrKeyStale and cache.ErrKeyExpired
+// of the cache.Cache.GetIncludingExpired() method, so it's cast regardless
+// of the error
+func castData[V any](data interface{}, err error) (value V, _ error) {
+	// use the two-value form to support nil data for interface types
+	value, _ = data.(V)
+	return value, err
+}
+
+func castHandler[K comparable, V any](handler Handler[K, V]) hashmap.Handler {
+	return func(key hashmap.Key, data interface{}) bool {
+		value, _ := data.(V)
+		return handler(key.(typedKey[K]).value, value)
+	}
+}
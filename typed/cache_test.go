@@ -0,0 +1,279 @@
+package typed
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cache "github.com/thewizardplusplus/go-cache"
+	"github.com/thewizardplusplus/go-cache/gc"
+	"github.com/thewizardplusplus/go-cache/models"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+type bucket struct {
+	key   string
+	value int
+	ttl   time.Duration
+}
+
+func TestNewCacheWithGC(test *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const gcPeriod = 10 * time.Millisecond
+	typedCache := NewCacheWithGC[string, int](
+		ctx,
+		cache.WithGCAndGCFactory(
+			func(storage hashmap.Storage, clock models.Clock) gc.GC {
+				return gc.NewTotalGC(storage, gc.TotalGCWithClock(clock))
+			},
+		),
+		cache.WithGCAndGCPeriod(gcPeriod),
+	)
+	typedCache.Set("one", 1, 0)
+	typedCache.Set("two", 2, -time.Second)
+
+	time.Sleep(gcPeriod * 10)
+
+	_, err := typedCache.Untyped().Get(typedKey[string]{"two"})
+	assert.Equal(test, cache.ErrKeyMissed, err)
+
+	value, err := typedCache.Get("one")
+	assert.Equal(test, 1, value)
+	assert.NoError(test, err)
+}
+
+func TestCache_Get(test *testing.T) {
+	type args struct {
+		key string
+	}
+
+	for _, data := range []struct {
+		name      string
+		buckets   []bucket
+		args      args
+		wantValue int
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			buckets:   []bucket{{key: "one", value: 1, ttl: time.Second}},
+			args:      args{key: "one"},
+			wantValue: 1,
+			wantErr:   nil,
+		},
+		{
+			name:      "error with a missed key",
+			buckets:   []bucket{{key: "one", value: 1, ttl: time.Second}},
+			args:      args{key: "two"},
+			wantValue: 0,
+			wantErr:   cache.ErrKeyMissed,
+		},
+		{
+			name:      "error with an expired key",
+			buckets:   []bucket{{key: "one", value: 1, ttl: -time.Second}},
+			args:      args{key: "one"},
+			wantValue: 0,
+			wantErr:   cache.ErrKeyExpired,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			typedCache := newCacheWithBuckets(data.buckets)
+			gotValue, gotErr := typedCache.Get(data.args.key)
+
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestCache_Get_withInterfaceValue(test *testing.T) {
+	typedCache := NewCache[string, error]()
+	typedCache.Set("key", nil, 0)
+
+	gotValue, gotErr := typedCache.Get("key")
+
+	assert.NoError(test, gotValue)
+	assert.NoError(test, gotErr)
+}
+
+func TestCache_Get_withStaleValue(test *testing.T) {
+	typedCache := NewCache[string, int](
+		cache.WithClock(clock),
+		cache.WithStaleTTL(time.Minute),
+	)
+	typedCache.Set("one", 1, -time.Second)
+
+	gotValue, gotErr := typedCache.Get("one")
+
+	assert.Equal(test, 1, gotValue)
+	assert.Equal(test, cache.ErrKeyStale, gotErr)
+}
+
+func TestCache_GetIncludingExpired(test *testing.T) {
+	typedCache := newCacheWithBuckets([]bucket{
+		{key: "one", value: 1, ttl: time.Second},
+		{key: "two", value: 2, ttl: -time.Second},
+	})
+
+	for _, data := range []struct {
+		key                string
+		wantValue          int
+		wantExpirationTime time.Time
+		wantErr            error
+	}{
+		{
+			key:                "one",
+			wantValue:          1,
+			wantExpirationTime: clock().Add(time.Second),
+			wantErr:            nil,
+		},
+		{
+			key:                "two",
+			wantValue:          2,
+			wantExpirationTime: clock().Add(-time.Second),
+			wantErr:            cache.ErrKeyExpired,
+		},
+		{
+			key:                "three",
+			wantValue:          0,
+			wantExpirationTime: time.Time{},
+			wantErr:            cache.ErrKeyMissed,
+		},
+	} {
+		test.Run(data.key, func(test *testing.T) {
+			gotValue, gotExpirationTime, gotErr :=
+				typedCache.GetIncludingExpired(data.key)
+
+			assert.Equal(test, data.wantValue, gotValue)
+			assert.Equal(test, data.wantExpirationTime, gotExpirationTime)
+			assert.Equal(test, data.wantErr, gotErr)
+		})
+	}
+}
+
+func TestCache_GetWithGC(test *testing.T) {
+	storage := hashmap.NewConcurrentHashMap()
+	typedCache := NewCache[string, int](cache.WithStorage(storage))
+	typedCache.Set("one", 1, -time.Second)
+
+	gotValue, gotErr := typedCache.GetWithGC("one")
+
+	_, ok := storage.Get(typedKey[string]{"one"})
+	assert.False(test, ok)
+	assert.Equal(test, 0, gotValue)
+	assert.Equal(test, cache.ErrKeyExpired, gotErr)
+}
+
+func TestCache_GetOrLoad(test *testing.T) {
+	typedCache := NewCache[string, int](cache.WithClock(clock))
+	for i := 0; i < 2; i++ {
+		gotValue, gotErr := typedCache.GetOrLoad(
+			context.Background(),
+			"one",
+			func(ctx context.Context, key string) (
+				value int,
+				ttl time.Duration,
+				err error,
+			) {
+				// the loader should be called only once
+				require.Equal(test, 0, i)
+				require.Equal(test, "one", key)
+
+				return 1, time.Second, nil
+			},
+		)
+
+		assert.Equal(test, 1, gotValue)
+		assert.NoError(test, gotErr)
+	}
+}
+
+func TestCache_Iterate(test *testing.T) {
+	typedCache := newCacheWithBuckets([]bucket{
+		{key: "one", value: 1},
+		{key: "two", value: 2, ttl: -time.Second},
+		{key: "three", value: 3},
+	})
+
+	var gotBuckets []bucket
+	gotOk := typedCache.Iterate(
+		context.Background(),
+		func(key string, value int) bool {
+			gotBuckets = append(gotBuckets, bucket{key: key, value: value})
+			return true
+		},
+	)
+
+	sort.Slice(gotBuckets, func(i int, j int) bool {
+		return gotBuckets[i].value < gotBuckets[j].value
+	})
+	assert.Equal(test, []bucket{{key: "one", value: 1}, {key: "three", value: 3}}, gotBuckets)
+	assert.True(test, gotOk)
+}
+
+func TestCache_IterateWithGC(test *testing.T) {
+	storage := hashmap.NewConcurrentHashMap()
+	typedCache := NewCache[string, int](cache.WithStorage(storage))
+	typedCache.Set("one", 1, 0)
+	typedCache.Set("two", 2, -time.Second)
+
+	var gotBuckets []bucket
+	gotOk := typedCache.IterateWithGC(
+		context.Background(),
+		func(key string, value int) bool {
+			gotBuckets = append(gotBuckets, bucket{key: key, value: value})
+			return true
+		},
+	)
+
+	_, ok := storage.Get(typedKey[string]{"two"})
+	assert.False(test, ok)
+	assert.Equal(test, []bucket{{key: "one", value: 1}}, gotBuckets)
+	assert.True(test, gotOk)
+}
+
+func TestCache_Set(test *testing.T) {
+	storage := hashmap.NewConcurrentHashMap()
+	typedCache :=
+		NewCache[string, int](cache.WithStorage(storage), cache.WithClock(clock))
+	typedCache.Set("one", 1, time.Second)
+
+	gotValue, ok := storage.Get(typedKey[string]{"one"})
+	require.True(test, ok)
+	assert.Equal(
+		test,
+		models.Value{Data: 1, ExpirationTime: clock().Add(time.Second)},
+		gotValue,
+	)
+}
+
+func TestCache_Delete(test *testing.T) {
+	typedCache := newCacheWithBuckets([]bucket{{key: "one", value: 1}})
+	typedCache.Delete("one")
+
+	_, err := typedCache.Get("one")
+	assert.Equal(test, cache.ErrKeyMissed, err)
+}
+
+func newCacheWithBuckets(buckets []bucket) Cache[string, int] {
+	typedCache := NewCache[string, int](cache.WithClock(clock))
+	for _, bucket := range buckets {
+		typedCache.Set(bucket.key, bucket.value, bucket.ttl)
+	}
+
+	return typedCache
+}
+
+func clock() time.Time {
+	return time.Date(
+		2006, time.January, 2, // year, month, day
+		15, 4, 5, // hour, minute, second
+		0,        // nanosecond
+		time.UTC, // location
+	)
+}
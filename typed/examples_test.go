@@ -0,0 +1,37 @@
+package typed_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cache "github.com/thewizardplusplus/go-cache"
+	"github.com/thewizardplusplus/go-cache/typed"
+)
+
+const (
+	gcPeriod     = time.Millisecond
+	exampleDelay = gcPeriod * 100
+)
+
+func ExampleNewCacheWithGC() {
+	timeZones := typed.NewCacheWithGC[string, int](
+		context.Background(),
+		cache.WithGCAndGCPeriod(gcPeriod),
+	)
+	timeZones.Set("EST", -5*60*60, exampleDelay/2)
+	timeZones.Set("CST", -6*60*60, exampleDelay/2)
+	timeZones.Set("MST", -7*60*60, exampleDelay/2)
+
+	estOffset, err := timeZones.Get("EST")
+	fmt.Println(estOffset, err)
+
+	time.Sleep(exampleDelay)
+
+	estOffset, err = timeZones.Get("EST")
+	fmt.Println(estOffset, err)
+
+	// Output:
+	// -18000 <nil>
+	// 0 key missed
+}
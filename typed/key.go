@@ -0,0 +1,22 @@
+package typed
+
+import (
+	"hash/maphash"
+
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+var seed = maphash.MakeSeed()
+
+type typedKey[K comparable] struct {
+	value K
+}
+
+func (key typedKey[K]) Hash() int {
+	return int(maphash.Comparable(seed, key.value))
+}
+
+func (key typedKey[K]) Equals(other hashmap.Key) bool {
+	otherKey, ok := other.(typedKey[K])
+	return ok && key.value == otherKey.value
+}
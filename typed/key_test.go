@@ -0,0 +1,78 @@
+package typed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hashmap "github.com/thewizardplusplus/go-hashmap"
+)
+
+func Test_typedKey_Hash(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		keyOne   typedKey[string]
+		keyTwo   typedKey[string]
+		wantHash assert.ComparisonAssertionFunc
+	}{
+		{
+			name:     "equal keys",
+			keyOne:   typedKey[string]{"key"},
+			keyTwo:   typedKey[string]{"key"},
+			wantHash: assert.Equal,
+		},
+		{
+			name:     "different keys",
+			keyOne:   typedKey[string]{"key one"},
+			keyTwo:   typedKey[string]{"key two"},
+			wantHash: assert.NotEqual,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			data.wantHash(test, data.keyOne.Hash(), data.keyTwo.Hash())
+		})
+	}
+}
+
+func Test_typedKey_Equals(test *testing.T) {
+	type args struct {
+		other hashmap.Key
+	}
+
+	for _, data := range []struct {
+		name string
+		key  typedKey[string]
+		args args
+		want assert.BoolAssertionFunc
+	}{
+		{
+			name: "equal keys",
+			key:  typedKey[string]{"key"},
+			args: args{
+				other: typedKey[string]{"key"},
+			},
+			want: assert.True,
+		},
+		{
+			name: "different keys",
+			key:  typedKey[string]{"key one"},
+			args: args{
+				other: typedKey[string]{"key two"},
+			},
+			want: assert.False,
+		},
+		{
+			name: "keys of different types",
+			key:  typedKey[string]{"23"},
+			args: args{
+				other: typedKey[int]{23},
+			},
+			want: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.key.Equals(data.args.other)
+
+			data.want(test, got)
+		})
+	}
+}
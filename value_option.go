@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"time"
+)
+
+type valueConfig struct {
+	cost      int64
+	isCostSet bool
+	idleTTL   time.Duration
+	tags      []string
+
+	recomputeCost time.Duration
+	staleTTL      time.Duration
+	isStaleTTLSet bool
+}
+
+// ValueOption ...
+type ValueOption func(config *valueConfig)
+
+// ValueWithCost ...
+//
+// Default: a cost calculated by the weigher or zero if the latter isn't set.
+//
+func ValueWithCost(cost int64) ValueOption {
+	return func(config *valueConfig) {
+		config.cost = cost
+		config.isCostSet = true
+	}
+}
+
+// ValueWithIdleTTL ...
+//
+// It's a time to live without access. Each successful getting postpones
+// the expiration, but not beyond the usual time to live, if the latter is set.
+// Zero idle time to live means no expiration on idleness.
+//
+// Default: 0.
+//
+func ValueWithIdleTTL(idleTTL time.Duration) ValueOption {
+	return func(config *valueConfig) {
+		config.idleTTL = idleTTL
+	}
+}
+
+// ValueWithTags ...
+//
+// Tags allow to delete a group of values at once via the Cache.InvalidateTag()
+// method. Setting of new data replaces tags of the previous one.
+//
+// Default: no tags.
+//
+func ValueWithTags(tags ...string) ValueOption {
+	return func(config *valueConfig) {
+		config.tags = append(config.tags, tags...)
+	}
+}
+
+// ValueWithRecomputeCost ...
+//
+// It's a duration of recomputation of the data. It's used for early
+// expiration (see the WithEarlyRecomputation() option). The Cache.GetOrLoad()
+// method sets it automatically to a duration of the loader call.
+//
+// Default: 0, i.e., no early expiration.
+//
+func ValueWithRecomputeCost(recomputeCost time.Duration) ValueOption {
+	return func(config *valueConfig) {
+		config.recomputeCost = recomputeCost
+	}
+}
+
+// ValueWithStaleTTL ...
+//
+// It's a time after the usual time to live during which the value is still
+// served, but as a stale one (see the ErrKeyStale error); so the usual time
+// to live becomes a soft one, and their sum becomes a hard one. Only the hard
+// time to live is taken into account by the Cache.TTL() method and garbage
+// collection. Zero stale time to live means no stale serving. It's ignored
+// for a value with infinite time to live.
+//
+// Default: the stale time to live set by the WithStaleTTL() option.
+//
+func ValueWithStaleTTL(staleTTL time.Duration) ValueOption {
+	return func(config *valueConfig) {
+		config.staleTTL = staleTTL
+		config.isStaleTTLSet = true
+	}
+}